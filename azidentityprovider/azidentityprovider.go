@@ -0,0 +1,22 @@
+// Package azidentityprovider exposes the provider to custom builds embedding it, as they can't import the internal
+// provider package.
+package azidentityprovider
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	internalprovider "github.com/rikpat/terraform-provider-azidentity/internal/provider"
+)
+
+// New returns the provider constructor to serve with providerserver.Serve, the same as the provider binary serves.
+func New(version string) func() provider.Provider {
+	return internalprovider.New(version)
+}
+
+// SetHTTPClient sets the HTTP client used for all token requests, including OIDC token requests of federated credentials.
+// It's intended for custom builds embedding the provider (custom transport, connection pooling, tracing), as an HTTP client
+// can't be passed through Terraform configuration. It must be called before the provider is served.
+func SetHTTPClient(client *http.Client) {
+	internalprovider.SetHTTPClient(client)
+}
@@ -0,0 +1,5 @@
+data "azidentity_chain_info" "current" {}
+
+output "credentials_in_chain" {
+  value = [for c in data.azidentity_chain_info.current.credentials : c.label if c.constructed]
+}
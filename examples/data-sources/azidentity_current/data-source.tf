@@ -0,0 +1,8 @@
+data "azidentity_current" "current" {}
+
+# Grant the deploying principal access to a key vault
+resource "azurerm_role_assignment" "deployer" {
+  scope                = azurerm_key_vault.example.id
+  role_definition_name = "Key Vault Secrets Officer"
+  principal_id         = data.azidentity_current.current.object_id
+}
@@ -0,0 +1,10 @@
+data "azidentity_meta" "current" {}
+
+resource "terraform_data" "requires_github" {
+  lifecycle {
+    precondition {
+      condition     = contains(data.azidentity_meta.current.credential_types, "github_oidc_credential")
+      error_message = "Provider version ${data.azidentity_meta.current.version} doesn't support GitHub OIDC credential."
+    }
+  }
+}
@@ -1,3 +1,38 @@
 ephemeral "azidentity_token" "token" {
   scopes = ["https://management.azure.com/.default"]
 }
+
+# Token formatted for CI env files, ex. to be appended to $GITHUB_ENV
+ephemeral "azidentity_token" "env" {
+  scopes          = ["https://management.azure.com/.default"]
+  dotenv_variable = "AZURE_ACCESS_TOKEN"
+}
+
+# Alias for https://ossrdbms-aad.database.windows.net/.default, adjusted to provider's cloud
+ephemeral "azidentity_token" "postgres" {
+  scopes = ["postgres"]
+}
+
+# Claims required by the application merged with claims returned in a CAE challenge
+ephemeral "azidentity_token" "cae" {
+  scopes     = ["https://graph.microsoft.com/.default"]
+  enable_cae = true
+  merge_claims = [
+    jsonencode({ access_token = { xms_cc = { values = ["cp1"] } } }),
+    var.claims_challenge,
+  ]
+}
+
+# Scopes of the provider's cloud, for modules used with multiple clouds
+ephemeral "azidentity_token" "storage" {
+  cloud_scopes = {
+    AzureChina = ["https://storage.azure.cn/.default"]
+    default    = ["https://storage.azure.com/.default"]
+  }
+}
+
+# Token for an AKS cluster in Kubernetes ExecCredential format, ex. for kubeconfig bootstrapping
+ephemeral "azidentity_token" "aks" {
+  scopes          = ["6dae42f8-4368-4678-94ff-3960e28e3630/.default"]
+  exec_credential = true
+}
@@ -0,0 +1,9 @@
+ephemeral "azidentity_tokens" "tokens" {
+  scopes = {
+    arm      = ["https://management.azure.com/.default"]
+    graph    = ["https://graph.microsoft.com/.default"]
+    postgres = ["postgres"]
+  }
+}
+
+# ephemeral.azidentity_tokens.tokens.tokens["graph"].token
@@ -0,0 +1,6 @@
+ephemeral "azidentity_validate" "auth" {
+  scope = "https://management.azure.com/.default"
+}
+
+# ephemeral.azidentity_validate.auth.success
+# ephemeral.azidentity_validate.auth.source_credential
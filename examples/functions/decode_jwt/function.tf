@@ -0,0 +1,8 @@
+# Claims of a token obtained outside of azidentity_token, the signature isn't verified
+locals {
+  claims = provider::azidentity::decode_jwt(var.token).payload
+}
+
+output "token_audience" {
+  value = local.claims.aud
+}
@@ -0,0 +1,13 @@
+ephemeral "azidentity_token" "postgres" {
+  scopes = ["postgres"]
+}
+
+provider "postgresql" {
+  # Defaults to database postgres, port 5432 and sslmode require
+  connection_string = provider::azidentity::postgres_connection_string(
+    "myserver.postgres.database.azure.com",
+    "terraform-deployer",
+    ephemeral.azidentity_token.postgres.token,
+    { database = "app" },
+  )
+}
@@ -51,3 +51,13 @@ provider "azidentity" {
     certificate_path = "./privkey_name.pem"
   }
 }
+
+provider "azidentity" {
+  alias       = "github"
+  credentials = ["github_oidc_credential", "azure_cli_credential"]
+  # Job needs `permissions: id-token: write`, tenant and client fall back to ARM_TENANT_ID and ARM_CLIENT_ID
+  github_oidc_credential = {
+    client_id = "db64e57b-7500-4ece-b682-e8fa8c20d9d5"
+    tenant_id = "6aafbe4c-9457-415e-b57d-834fe4d09c7d"
+  }
+}
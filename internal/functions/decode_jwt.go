@@ -0,0 +1,138 @@
+package functions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DecodeJWTFunction{}
+
+func NewDecodeJWTFunction() function.Function {
+	return &DecodeJWTFunction{}
+}
+
+// DecodeJWTFunction decodes header and payload of a JWT without verifying its signature.
+type DecodeJWTFunction struct{}
+
+var decodeJWTReturnTypes = map[string]attr.Type{
+	"header":          types.DynamicType,
+	"payload":         types.DynamicType,
+	"valid_structure": types.BoolType,
+}
+
+func (f *DecodeJWTFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decode_jwt"
+}
+
+func (f *DecodeJWTFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Decode header and payload of a JWT",
+		MarkdownDescription: "Decodes header and payload of a JWT, ex. an access token obtained outside of `azidentity_token`, into objects with claims keeping their JSON types. **The signature isn't verified**, so claims of untrusted tokens can't be relied on. `valid_structure` is true when both segments are JSON objects and the header has an `alg`. It's an error when the token doesn't have three dot separated base64url segments.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "token",
+				MarkdownDescription: "JWT to decode",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: decodeJWTReturnTypes,
+		},
+	}
+}
+
+func (f *DecodeJWTFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var token string
+	if resp.Error = req.Arguments.Get(ctx, &token); resp.Error != nil {
+		return
+	}
+
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("JWT must have 3 dot separated segments, got %d", len(segments)))
+		return
+	}
+	header, err := decodeSegment(segments[0])
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Failed decoding JWT header: %s", err))
+		return
+	}
+	payload, err := decodeSegment(segments[1])
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Failed decoding JWT payload: %s", err))
+		return
+	}
+	_, hasAlg := header["alg"]
+
+	result, diags := types.ObjectValue(decodeJWTReturnTypes, map[string]attr.Value{
+		"header":          segmentValue(header),
+		"payload":         segmentValue(payload),
+		"valid_structure": types.BoolValue(header != nil && payload != nil && hasAlg),
+	})
+	if diags.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diags)
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+// Decode a base64url segment of a JWT. Segments which aren't JSON objects are nil, only invalid base64 is an error.
+func decodeSegment(segment string) (map[string]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]any{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, nil
+	}
+	return object, nil
+}
+
+// Convert a decoded segment to a dynamic value, null when it isn't a JSON object.
+func segmentValue(object map[string]any) types.Dynamic {
+	if object == nil {
+		return types.DynamicNull()
+	}
+	return types.DynamicValue(jsonValue(object))
+}
+
+// Convert a decoded JSON value to a Terraform value, keeping JSON types of nested values.
+func jsonValue(value any) attr.Value {
+	switch value := value.(type) {
+	case string:
+		return types.StringValue(value)
+	case bool:
+		return types.BoolValue(value)
+	case float64:
+		return types.NumberValue(big.NewFloat(value))
+	case []any:
+		elementTypes := make([]attr.Type, 0, len(value))
+		elements := make([]attr.Value, 0, len(value))
+		for _, element := range value {
+			converted := jsonValue(element)
+			elementTypes = append(elementTypes, converted.Type(context.Background()))
+			elements = append(elements, converted)
+		}
+		return types.TupleValueMust(elementTypes, elements)
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(value))
+		attributes := make(map[string]attr.Value, len(value))
+		for name, element := range value {
+			converted := jsonValue(element)
+			attributeTypes[name] = converted.Type(context.Background())
+			attributes[name] = converted
+		}
+		return types.ObjectValueMust(attributeTypes, attributes)
+	}
+	// JSON null, a concrete type is needed inside objects and lists
+	return types.StringNull()
+}
@@ -0,0 +1,97 @@
+package functions
+
+import (
+	"context"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Run decode_jwt with the token, returning the result object or the error.
+func runDecodeJWT(t *testing.T, token string) (types.Object, *function.FuncError) {
+	t.Helper()
+	resp := &function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(decodeJWTReturnTypes))}
+	NewDecodeJWTFunction().Run(context.Background(), function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(token)}),
+	}, resp)
+	if resp.Error != nil {
+		return types.Object{}, resp.Error
+	}
+	result, ok := resp.Result.Value().(types.Object)
+	if !ok {
+		t.Fatalf("result = %T, want an object", resp.Result.Value())
+	}
+	return result, nil
+}
+
+func segment(json string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(json))
+}
+
+func TestDecodeJWT(t *testing.T) {
+	token := strings.Join([]string{segment(`{"alg":"RS256","typ":"JWT"}`), segment(`{"sub":"user","exp":1700000000,"roles":["a","b"]}`), "signature"}, ".")
+	result, err := runDecodeJWT(t, token)
+	if err != nil {
+		t.Fatalf("error = %s", err)
+	}
+	attributes := result.Attributes()
+	if valid := attributes["valid_structure"]; !valid.Equal(types.BoolValue(true)) {
+		t.Errorf("valid_structure = %s, want true", valid)
+	}
+	payload, ok := attributes["payload"].(types.Dynamic).UnderlyingValue().(types.Object)
+	if !ok {
+		t.Fatalf("payload = %s, want an object", attributes["payload"])
+	}
+	if sub := payload.Attributes()["sub"]; !sub.Equal(types.StringValue("user")) {
+		t.Errorf("sub = %s, want user", sub)
+	}
+	if exp := payload.Attributes()["exp"]; !exp.Equal(types.NumberValue(big.NewFloat(1700000000))) {
+		t.Errorf("exp = %s, want the number 1700000000", exp)
+	}
+	if _, ok := payload.Attributes()["roles"].(types.Tuple); !ok {
+		t.Errorf("roles = %s, want a tuple", payload.Attributes()["roles"])
+	}
+}
+
+func TestDecodeJWTWithoutAlg(t *testing.T) {
+	result, err := runDecodeJWT(t, strings.Join([]string{segment(`{"typ":"JWT"}`), segment(`{}`), ""}, "."))
+	if err != nil {
+		t.Fatalf("error = %s", err)
+	}
+	if valid := result.Attributes()["valid_structure"]; !valid.Equal(types.BoolValue(false)) {
+		t.Errorf("valid_structure = %s, want false", valid)
+	}
+}
+
+func TestDecodeJWTInvalid(t *testing.T) {
+	header, payload := segment(`{"alg":"none"}`), segment(`{"sub":"user"}`)
+	tests := map[string]struct {
+		token string
+		want  string
+	}{
+		"two segments":   {token: header + "." + payload, want: "JWT must have 3 dot separated segments, got 2"},
+		"four segments":  {token: header + "." + payload + ".signature.extra", want: "JWT must have 3 dot separated segments, got 4"},
+		"bad base64":     {token: header + ".not*base64.signature", want: "Failed decoding JWT payload"},
+		"bad header":     {token: "%%%." + payload + ".signature", want: "Failed decoding JWT header"},
+		"empty argument": {token: "", want: "got 1"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := runDecodeJWT(t, test.token)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Text, test.want) {
+				t.Errorf("error = %q, want %q", err.Text, test.want)
+			}
+			if err.FunctionArgument == nil || *err.FunctionArgument != 0 {
+				t.Errorf("error isn't for the token argument: %v", err.FunctionArgument)
+			}
+		})
+	}
+}
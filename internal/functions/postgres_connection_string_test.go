@@ -0,0 +1,121 @@
+package functions
+
+import (
+	"context"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testPostgresHost = "myserver.postgres.database.azure.com"
+
+// Run postgres_connection_string, returning the connection string or the error.
+func runPostgresConnectionString(t *testing.T, user string, token string, options types.Dynamic) (string, *function.FuncError) {
+	t.Helper()
+	resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	NewPostgresConnectionStringFunction().Run(context.Background(), function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(testPostgresHost), types.StringValue(user), types.StringValue(token), options}),
+	}, resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+	result, ok := resp.Result.Value().(types.String)
+	if !ok {
+		t.Fatalf("result = %T, want a string", resp.Result.Value())
+	}
+	return result.ValueString(), nil
+}
+
+func objectOptions(values map[string]attr.Value) types.Dynamic {
+	attributeTypes := make(map[string]attr.Type, len(values))
+	for name, value := range values {
+		attributeTypes[name] = value.Type(context.Background())
+	}
+	return types.DynamicValue(types.ObjectValueMust(attributeTypes, values))
+}
+
+func TestPostgresConnectionString(t *testing.T) {
+	tests := map[string]struct {
+		options types.Dynamic
+		want    string
+	}{
+		"defaults":        {options: types.DynamicNull(), want: "postgresql://admin:token@" + testPostgresHost + ":5432/postgres?sslmode=require"},
+		"null underlying": {options: types.DynamicValue(types.ObjectNull(map[string]attr.Type{})), want: "postgresql://admin:token@" + testPostgresHost + ":5432/postgres?sslmode=require"},
+		"database":        {options: objectOptions(map[string]attr.Value{"database": types.StringValue("app")}), want: "postgresql://admin:token@" + testPostgresHost + ":5432/app?sslmode=require"},
+		"port":            {options: objectOptions(map[string]attr.Value{"port": types.NumberValue(big.NewFloat(6432))}), want: "postgresql://admin:token@" + testPostgresHost + ":6432/postgres?sslmode=require"},
+		"port from a map": {options: types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{"port": types.StringValue("6432")})), want: "postgresql://admin:token@" + testPostgresHost + ":6432/postgres?sslmode=require"},
+		"sslmode":         {options: objectOptions(map[string]attr.Value{"sslmode": types.StringValue("verify-full")}), want: "postgresql://admin:token@" + testPostgresHost + ":5432/postgres?sslmode=verify-full"},
+		"null option":     {options: objectOptions(map[string]attr.Value{"database": types.StringNull()}), want: "postgresql://admin:token@" + testPostgresHost + ":5432/postgres?sslmode=require"},
+		"all options":     {options: objectOptions(map[string]attr.Value{"database": types.StringValue("app"), "port": types.NumberValue(big.NewFloat(6432)), "sslmode": types.StringValue("verify-ca")}), want: "postgresql://admin:token@" + testPostgresHost + ":6432/app?sslmode=verify-ca"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := runPostgresConnectionString(t, "admin", "token", test.options)
+			if err != nil {
+				t.Fatalf("error = %s", err)
+			}
+			if got != test.want {
+				t.Errorf("connection string = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPostgresConnectionStringEscaping(t *testing.T) {
+	user := `my group "admins"`
+	token := `to ken"with\backslash:@/?`
+	got, err := runPostgresConnectionString(t, user, token, types.DynamicNull())
+	if err != nil {
+		t.Fatalf("error = %s", err)
+	}
+	if strings.ContainsAny(strings.SplitN(got, "@"+testPostgresHost, 2)[0], ` "\`) {
+		t.Errorf("connection string %s has unescaped characters", got)
+	}
+	parsed, parseErr := url.Parse(got)
+	if parseErr != nil {
+		t.Fatalf("parsing %s: %s", got, parseErr)
+	}
+	if parsed.User.Username() != user {
+		t.Errorf("user = %q, want %q", parsed.User.Username(), user)
+	}
+	if password, _ := parsed.User.Password(); password != token {
+		t.Errorf("token = %q, want %q", password, token)
+	}
+	if parsed.Host != testPostgresHost+":5432" {
+		t.Errorf("host = %s, want %s:5432", parsed.Host, testPostgresHost)
+	}
+}
+
+func TestPostgresConnectionStringInvalid(t *testing.T) {
+	tests := map[string]struct {
+		user    string
+		token   string
+		options types.Dynamic
+		want    string
+	}{
+		"empty token":       {user: "admin", options: types.DynamicNull(), want: "Value must not be empty"},
+		"empty user":        {token: "token", options: types.DynamicNull(), want: "Value must not be empty"},
+		"insecure sslmode":  {user: "admin", token: "token", options: objectOptions(map[string]attr.Value{"sslmode": types.StringValue("disable")}), want: "sslmode must be one of"},
+		"port out of range": {user: "admin", token: "token", options: objectOptions(map[string]attr.Value{"port": types.NumberValue(big.NewFloat(70000))}), want: "port must be a number"},
+		"fractional port":   {user: "admin", token: "token", options: objectOptions(map[string]attr.Value{"port": types.NumberValue(big.NewFloat(5432.5))}), want: "port must be a number"},
+		"empty database":    {user: "admin", token: "token", options: objectOptions(map[string]attr.Value{"database": types.StringValue("")}), want: "database must be a non-empty string"},
+		"unknown option":    {user: "admin", token: "token", options: objectOptions(map[string]attr.Value{"schema": types.StringValue("public")}), want: "unsupported option 'schema'"},
+		"not an object":     {user: "admin", token: "token", options: types.DynamicValue(types.StringValue("app")), want: "options must be an object"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := runPostgresConnectionString(t, test.user, test.token, test.options)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Text, test.want) {
+				t.Errorf("error = %q, want %q", err.Text, test.want)
+			}
+		})
+	}
+}
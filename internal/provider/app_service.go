@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Check App Service env variables of the managed identity for the running deployment slot. Each slot has its own
+// identity, so an identity enabled on the production slot doesn't apply to other slots. Outside App Service
+// (WEBSITE_SITE_NAME not set) nothing is checked.
+func appServiceSlotDiagnostics(ctx context.Context, p path.Path, id azidentity.ManagedIDKind) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	site, ok := os.LookupEnv("WEBSITE_SITE_NAME")
+	if !ok {
+		return diags
+	}
+	slot, ok := os.LookupEnv("WEBSITE_SLOT_NAME")
+	if !ok || slot == "" {
+		slot = "Production"
+	}
+	identity := "system-assigned identity"
+	if id != nil {
+		identity = fmt.Sprintf("user-assigned identity '%s'", id)
+	}
+	tflog.Info(ctx, fmt.Sprintf("Using %s of App Service '%s' slot '%s'", identity, site, slot))
+
+	_, hasEndpoint := os.LookupEnv("IDENTITY_ENDPOINT")
+	_, hasHeader := os.LookupEnv("IDENTITY_HEADER")
+	switch {
+	case !hasEndpoint && !hasHeader:
+		diags.AddAttributeWarning(p, "Managed identity not enabled for App Service slot",
+			fmt.Sprintf("App Service '%s' slot '%s' has no IDENTITY_ENDPOINT and IDENTITY_HEADER. Identities are enabled per slot, "+
+				"an identity of the production slot isn't available in other slots. Enable an identity on the slot itself.", site, slot))
+	case hasEndpoint != hasHeader:
+		diags.AddAttributeWarning(p, "Inconsistent App Service identity configuration",
+			fmt.Sprintf("App Service '%s' slot '%s' has only one of IDENTITY_ENDPOINT and IDENTITY_HEADER set. They're set together "+
+				"by the platform, check app settings overriding either of them.", site, slot))
+	}
+	return diags
+}
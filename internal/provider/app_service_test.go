@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestAppServiceSlotDiagnostics(t *testing.T) {
+	tests := map[string]struct {
+		env         map[string]string
+		id          azidentity.ManagedIDKind
+		wantSummary string
+		wantDetail  string
+	}{
+		"outside App Service": {
+			env: map[string]string{"WEBSITE_SLOT_NAME": "staging"},
+		},
+		"slot with identity": {
+			env: map[string]string{"WEBSITE_SITE_NAME": "app", "WEBSITE_SLOT_NAME": "staging", "IDENTITY_ENDPOINT": "http://127.0.0.1:41741/msi/token", "IDENTITY_HEADER": "header"},
+		},
+		"slot with user-assigned identity": {
+			env: map[string]string{"WEBSITE_SITE_NAME": "app", "WEBSITE_SLOT_NAME": "staging", "IDENTITY_ENDPOINT": "http://127.0.0.1:41741/msi/token", "IDENTITY_HEADER": "header"},
+			id:  azidentity.ClientID(testClientID),
+		},
+		"slot without identity": {
+			env:         map[string]string{"WEBSITE_SITE_NAME": "app", "WEBSITE_SLOT_NAME": "staging"},
+			wantSummary: "Managed identity not enabled for App Service slot",
+			wantDetail:  "App Service 'app' slot 'staging'",
+		},
+		"production slot without identity": {
+			env:         map[string]string{"WEBSITE_SITE_NAME": "app"},
+			wantSummary: "Managed identity not enabled for App Service slot",
+			wantDetail:  "App Service 'app' slot 'Production'",
+		},
+		"endpoint without header": {
+			env:         map[string]string{"WEBSITE_SITE_NAME": "app", "WEBSITE_SLOT_NAME": "staging", "IDENTITY_ENDPOINT": "http://127.0.0.1:41741/msi/token"},
+			wantSummary: "Inconsistent App Service identity configuration",
+			wantDetail:  "only one of IDENTITY_ENDPOINT and IDENTITY_HEADER",
+		},
+		"header without endpoint": {
+			env:         map[string]string{"WEBSITE_SITE_NAME": "app", "WEBSITE_SLOT_NAME": "staging", "IDENTITY_HEADER": "header"},
+			wantSummary: "Inconsistent App Service identity configuration",
+			wantDetail:  "only one of IDENTITY_ENDPOINT and IDENTITY_HEADER",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unsetEnv(t, "WEBSITE_SITE_NAME", "WEBSITE_SLOT_NAME", "IDENTITY_ENDPOINT", "IDENTITY_HEADER")
+			for env, value := range test.env {
+				t.Setenv(env, value)
+			}
+			diags := appServiceSlotDiagnostics(context.Background(), path.Root("managed_identity_credential"), test.id)
+			if test.wantSummary == "" {
+				if len(diags) != 0 {
+					t.Errorf("diagnostics = %v, want none", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags.WarningsCount() != 1 {
+				t.Fatalf("diagnostics = %v, want one warning", diags)
+			}
+			if got := diags[0].Summary(); got != test.wantSummary {
+				t.Errorf("summary = %q, want %q", got, test.wantSummary)
+			}
+			if detail := diags[0].Detail(); !strings.Contains(detail, test.wantDetail) {
+				t.Errorf("detail %q doesn't contain %q", detail, test.wantDetail)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Get the directory authentication records of interactive credentials are stored in, next to other cached files of the user.
+func authenticationRecordDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, persistentCacheName), nil
+}
+
+// Path of the authentication record of a credential type signing in to the tenant with the client ID.
+func authenticationRecordPath(dir string, credentialType string, tenantID string, clientID string) string {
+	if tenantID == "" {
+		tenantID = "organizations"
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%s-%s.json", credentialType, url.PathEscape(tenantID), url.PathEscape(clientID)))
+}
+
+// Load a stored authentication record, zero value when there's none yet. An unreadable record is ignored, the user
+// signs in again and it's replaced.
+func loadAuthenticationRecord(ctx context.Context, file string) azidentity.AuthenticationRecord {
+	var record azidentity.AuthenticationRecord
+	content, err := os.ReadFile(file)
+	if err == nil {
+		err = json.Unmarshal(content, &record)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		tflog.Warn(ctx, fmt.Sprintf("Ignoring the authentication record in %s: %s", file, err))
+		return azidentity.AuthenticationRecord{}
+	}
+	return record
+}
+
+// Store the authentication record, readable only by the current user. It holds account details, not tokens.
+func saveAuthenticationRecord(file string, record azidentity.AuthenticationRecord) error {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, content, 0o600)
+}
+
+// Interactive credential of the SDK, which signs in the user explicitly.
+type authenticator interface {
+	azcore.TokenCredential
+	Authenticate(ctx context.Context, opts *policy.TokenRequestOptions) (azidentity.AuthenticationRecord, error)
+}
+
+var _ azcore.TokenCredential = &authenticatingCredential{}
+
+// authenticatingCredential signs in the user on the first token request and stores the authentication record. Later
+// runs load the record, so the credential finds the account in the persistent cache instead of prompting again.
+type authenticatingCredential struct {
+	credential authenticator
+	file       string
+	mu         sync.Mutex
+	done       bool
+}
+
+// GetToken authenticates once, then requests the token from the wrapped credential. Failing to store the record only
+// means the next run prompts again, so it's logged instead of failing the request.
+func (c *authenticatingCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.mu.Lock()
+	if !c.done {
+		record, err := c.credential.Authenticate(ctx, &opts)
+		if err != nil {
+			c.mu.Unlock()
+			return azcore.AccessToken{}, err
+		}
+		c.done = true
+		if err := saveAuthenticationRecord(c.file, record); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to store the authentication record in %s: %s", c.file, err))
+		}
+	}
+	c.mu.Unlock()
+	return c.credential.GetToken(ctx, opts)
+}
+
+// Wrap an interactive credential to store its authentication record on the first sign in, unless there's no file to
+// store it in or a stored record was already loaded into its options.
+func withAuthenticationRecord(cred authenticator, file string, loaded azidentity.AuthenticationRecord) azcore.TokenCredential {
+	if file == "" || loaded != (azidentity.AuthenticationRecord{}) {
+		return cred
+	}
+	return &authenticatingCredential{credential: cred, file: file}
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+type fakeAuthenticator struct {
+	record          azidentity.AuthenticationRecord
+	authentications int
+}
+
+func (f *fakeAuthenticator) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token"}, nil
+}
+
+func (f *fakeAuthenticator) Authenticate(context.Context, *policy.TokenRequestOptions) (azidentity.AuthenticationRecord, error) {
+	f.authentications++
+	return f.record, nil
+}
+
+func TestAuthenticationRecordStoredOnFirstSignIn(t *testing.T) {
+	ctx := context.Background()
+	file := authenticationRecordPath(filepath.Join(t.TempDir(), "records"), "device_code_credential", "", testClientID)
+	record := azidentity.AuthenticationRecord{
+		Authority:     "login.microsoftonline.com",
+		ClientID:      testClientID,
+		HomeAccountID: "object.tenant",
+		TenantID:      testTenantID,
+		Username:      "user@example.com",
+		Version:       "1.0",
+	}
+	fake := &fakeAuthenticator{record: record}
+
+	cred := withAuthenticationRecord(fake, file, loadAuthenticationRecord(ctx, file))
+	for range 2 {
+		if _, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com/.default"}}); err != nil {
+			t.Fatalf("GetToken: %s", err)
+		}
+	}
+	if fake.authentications != 1 {
+		t.Errorf("authenticated %d times, expected once", fake.authentications)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("record not stored: %s", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("record is stored with mode %o, expected 600", info.Mode().Perm())
+	}
+
+	loaded := loadAuthenticationRecord(ctx, file)
+	if loaded != record {
+		t.Errorf("loaded record %+v, expected %+v", loaded, record)
+	}
+	if cred := withAuthenticationRecord(fake, file, loaded); cred != fake {
+		t.Error("credential with a loaded record is wrapped to authenticate again")
+	}
+}
+
+func TestAuthenticationRecordUnreadable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "record.json")
+	if err := os.WriteFile(file, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if record := loadAuthenticationRecord(context.Background(), file); record != (azidentity.AuthenticationRecord{}) {
+		t.Errorf("loaded %+v from an invalid record", record)
+	}
+}
@@ -0,0 +1,706 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	testTenantID = "00000000-0000-0000-0000-000000000001"
+	testClientID = "00000000-0000-0000-0000-000000000002"
+	// Password of the encrypted certificates in testdata
+	testCertificatePassword = "fixture-password"
+)
+
+// Unset env variables for the duration of the test, so variables of the environment running tests don't leak in.
+func unsetEnv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+		if err := os.Unsetenv(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Value of a credential block of the provider schema, with the given attribute values and null for the others.
+func credentialObject(t *testing.T, block string, values map[string]attr.Value) types.Object {
+	t.Helper()
+	resp := &provider.SchemaResponse{}
+	New("test")().Schema(context.Background(), provider.SchemaRequest{}, resp)
+	objectType, ok := resp.Schema.Attributes[block].GetType().(types.ObjectType)
+	if !ok {
+		t.Fatalf("%s isn't an object attribute", block)
+	}
+	attributes := make(map[string]attr.Value, len(objectType.AttrTypes))
+	for name, attributeType := range objectType.AttrTypes {
+		attributes[name] = nullValue(t, attributeType)
+	}
+	for name, value := range values {
+		if _, ok := attributes[name]; !ok {
+			t.Fatalf("unknown attribute %s of %s", name, block)
+		}
+		attributes[name] = value
+	}
+	object, diags := types.ObjectValue(objectType.AttrTypes, attributes)
+	if diags.HasError() {
+		t.Fatalf("object of %s: %v", block, diags)
+	}
+	return object
+}
+
+// Null value of an attribute type.
+func nullValue(t *testing.T, attributeType attr.Type) attr.Value {
+	t.Helper()
+	value, err := attributeType.ValueFromTerraform(context.Background(), tftypes.NewValue(attributeType.TerraformType(context.Background()), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestClientCertificatePasswordFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		env     map[string]string
+		wantErr bool
+	}{
+		"azure variable": {
+			env: map[string]string{"AZURE_CLIENT_CERTIFICATE_PASSWORD": testCertificatePassword},
+		},
+		"azurerm variable": {
+			env: map[string]string{"ARM_CLIENT_CERTIFICATE_PASSWORD": testCertificatePassword},
+		},
+		"azurerm variable first": {
+			env: map[string]string{"ARM_CLIENT_CERTIFICATE_PASSWORD": testCertificatePassword, "AZURE_CLIENT_CERTIFICATE_PASSWORD": "wrong"},
+		},
+		"wrong password": {
+			env:     map[string]string{"AZURE_CLIENT_CERTIFICATE_PASSWORD": "wrong"},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unsetEnv(t, "ARM_CLIENT_CERTIFICATE_PASSWORD", "AZURE_CLIENT_CERTIFICATE_PASSWORD")
+			for env, value := range test.env {
+				t.Setenv(env, value)
+			}
+			in := credentialObject(t, "client_certificate_credential", map[string]attr.Value{
+				"tenant_id":        types.StringValue(testTenantID),
+				"client_id":        types.StringValue(testClientID),
+				"certificate_path": types.StringValue("testdata/certificate_encrypted.pfx"),
+			})
+			diags := diag.Diagnostics{}
+			cred, err := newClientCertificateCredential(context.Background(), in, &diags, path.Root("client_certificate_credential"), azcore.ClientOptions{}, credentialCommon{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diags.HasError() != test.wantErr {
+				t.Fatalf("diagnostics = %v, want error %t", diags, test.wantErr)
+			}
+			if (cred == nil) != test.wantErr {
+				t.Errorf("credential = %v, want error %t", cred, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	tests := map[string]struct {
+		file        string
+		password    string
+		wantSummary string
+		wantHint    bool
+	}{
+		"pem": {
+			file: "testdata/certificate.pem",
+		},
+		"pfx": {
+			file: "testdata/certificate.pfx",
+		},
+		"encrypted pfx": {
+			file:     "testdata/certificate_encrypted.pfx",
+			password: testCertificatePassword,
+		},
+		"encrypted pfx without password": {
+			file:        "testdata/certificate_encrypted.pfx",
+			wantSummary: "Failed to parse certificate",
+			wantHint:    true,
+		},
+		"encrypted pfx with wrong password": {
+			file:        "testdata/certificate_encrypted.pfx",
+			password:    "wrong",
+			wantSummary: "Failed to parse certificate",
+		},
+		"encrypted pem": {
+			file:        "testdata/certificate_encrypted.pem",
+			wantSummary: "Encrypted PEM private key not supported",
+		},
+		"encrypted pem with password": {
+			file:        "testdata/certificate_encrypted.pem",
+			password:    testCertificatePassword,
+			wantSummary: "Encrypted PEM private key not supported",
+		},
+		"missing file": {
+			file:        "testdata/missing.pem",
+			wantSummary: "Failed to read certificate file",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := diag.Diagnostics{}
+			cert, key, ok := loadClientCertificate(test.file, test.password, &diags, path.Root("client_certificate_credential"))
+			if test.wantSummary == "" {
+				if !ok || diags.HasError() {
+					t.Fatalf("failed loading %s: %v", test.file, diags)
+				}
+				if len(cert) != 1 || key == nil {
+					t.Errorf("got %d certificates and key %v, want 1 certificate with a key", len(cert), key)
+				}
+				return
+			}
+			if ok || diags.ErrorsCount() != 1 {
+				t.Fatalf("diagnostics = %v, want one error", diags)
+			}
+			if got := diags.Errors()[0].Summary(); got != test.wantSummary {
+				t.Errorf("summary = %q, want %q", got, test.wantSummary)
+			}
+			if hint := strings.Contains(diags.Errors()[0].Detail(), "set certificate_password"); hint != test.wantHint {
+				t.Errorf("password hint = %t, want %t in %q", hint, test.wantHint, diags.Errors()[0].Detail())
+			}
+		})
+	}
+}
+
+func TestClientCertificateFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		password string
+		wantErr  bool
+	}{
+		"path and password": {
+			password: testCertificatePassword,
+		},
+		"path without password": {
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unsetEnv(t, "ARM_CLIENT_CERTIFICATE_PASSWORD", "AZURE_CLIENT_CERTIFICATE_PASSWORD")
+			t.Setenv("AZURE_TENANT_ID", testTenantID)
+			t.Setenv("AZURE_CLIENT_ID", testClientID)
+			t.Setenv("AZURE_CLIENT_CERTIFICATE_PATH", "testdata/certificate_encrypted.pfx")
+			if test.password != "" {
+				t.Setenv("AZURE_CLIENT_CERTIFICATE_PASSWORD", test.password)
+			}
+			in := credentialObject(t, "client_certificate_credential", nil)
+			diags := diag.Diagnostics{}
+			cred, err := newClientCertificateCredential(context.Background(), in, &diags, path.Root("client_certificate_credential"), azcore.ClientOptions{}, credentialCommon{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !test.wantErr {
+				if diags.HasError() || cred == nil {
+					t.Fatalf("credential not set up from env: %v", diags)
+				}
+				return
+			}
+			if cred != nil || !diags.HasError() {
+				t.Fatalf("credential set up without the password of an encrypted certificate")
+			}
+			if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, "AZURE_CLIENT_CERTIFICATE_PASSWORD") {
+				t.Errorf("error doesn't mention the password env variable: %s", detail)
+			}
+		})
+	}
+}
+
+func TestParseClientCertificateWithoutPrivateKey(t *testing.T) {
+	certData, err := os.ReadFile("testdata/certificate_without_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags := diag.Diagnostics{}
+	if _, _, ok := parseClientCertificate(certData, "certificate_pem", "", &diags, path.Root("client_certificate_credential")); ok {
+		t.Fatal("parsed a certificate without a private key")
+	}
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("diagnostics = %v, want one error", diags)
+	}
+	if got := diags.Errors()[0].Summary(); got != "Certificate has no private key" {
+		t.Errorf("summary = %q, want Certificate has no private key", got)
+	}
+	if detail := diags.Errors()[0].Detail(); !strings.HasPrefix(detail, "certificate_pem doesn't contain a private key") {
+		t.Errorf("detail doesn't name the source: %s", detail)
+	}
+}
+
+// Fake Entra ID and Azure Pipelines OIDC endpoint, recording the OIDC token requests of Azure Pipelines credentials.
+type fakePipelinesServer struct {
+	*httptest.Server
+	mu             sync.Mutex
+	authorizations []string
+	connectionIDs  []string
+}
+
+func newFakePipelinesServer(t *testing.T) *fakePipelinesServer {
+	t.Helper()
+	s := &fakePipelinesServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+		s.connectionIDs = append(s.connectionIDs, r.URL.Query().Get("serviceConnectionId"))
+		s.mu.Unlock()
+		_, _ = w.Write([]byte(`{"oidcToken":"oidc-token"}`))
+	})
+	mux.HandleFunc("/"+testTenantID+"/v2.0/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"authorization_endpoint": s.URL + "/" + testTenantID + "/oauth2/v2.0/authorize",
+			"token_endpoint":         s.URL + "/" + testTenantID + "/oauth2/v2.0/token",
+			"issuer":                 s.URL + "/" + testTenantID + "/v2.0",
+		})
+	})
+	mux.HandleFunc("/"+testTenantID+"/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-token", "token_type": "Bearer", "expires_in": 3600})
+	})
+	s.Server = httptest.NewTLSServer(mux)
+	t.Cleanup(s.Close)
+	t.Setenv("SYSTEM_OIDCREQUESTURI", s.URL+"/oidc")
+	return s
+}
+
+// Client options sending requests to the fake server.
+func (s *fakePipelinesServer) clientOptions() azcore.ClientOptions {
+	return azcore.ClientOptions{
+		Cloud:     cloud.Configuration{ActiveDirectoryAuthorityHost: s.URL + "/"},
+		Transport: s.Client(),
+	}
+}
+
+// Authorization header and service connection ID of the OIDC token requests.
+func (s *fakePipelinesServer) requests() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.authorizations), slices.Clone(s.connectionIDs)
+}
+
+func TestAzurePipelinesCredentialArguments(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-access-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		props     APcP
+		wantToken string
+	}{
+		"system access token": {
+			props: APcP{
+				TenantID:            testTenantID,
+				ClientID:            testClientID,
+				ServiceConnectionID: "service-connection-id",
+				SystemAccessToken:   "system-access-token",
+			},
+			wantToken: "system-access-token",
+		},
+		"system access token file": {
+			props: APcP{
+				TenantID:              testTenantID,
+				ClientID:              testClientID,
+				ServiceConnectionID:   "service-connection-id",
+				SystemAccessToken:     "system-access-token",
+				SystemAccessTokenFile: tokenFile,
+			},
+			wantToken: "file-access-token",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newFakePipelinesServer(t)
+			cred, err := newAzurePipelinesCredential(test.props, server.clientOptions(), credentialCommon{disableInstanceDiscovery: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com/.default"}}); err != nil {
+				t.Fatal(err)
+			}
+			authorizations, connectionIDs := server.requests()
+			if len(authorizations) != 1 {
+				t.Fatalf("%d OIDC token requests, want 1", len(authorizations))
+			}
+			if want := "Bearer " + test.wantToken; authorizations[0] != want {
+				t.Errorf("OIDC request authorization = %q, want %q", authorizations[0], want)
+			}
+			if connectionIDs[0] != test.props.ServiceConnectionID {
+				t.Errorf("OIDC request service connection = %q, want %q", connectionIDs[0], test.props.ServiceConnectionID)
+			}
+		})
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := map[string]struct {
+		in          attr.Value
+		tag         string
+		env         map[string]string
+		variables   map[string]string
+		envOverride string
+		skipMissing bool
+		want        any
+		wantSummary string
+	}{
+		"string": {
+			in:   types.StringValue("value"),
+			want: "value",
+		},
+		"bool": {
+			in:   types.BoolValue(true),
+			want: true,
+		},
+		"int64": {
+			in:   types.Int64Value(42),
+			want: int64(42),
+		},
+		"framework bool": {
+			in:   types.BoolValue(true),
+			want: types.BoolValue(true),
+		},
+		"framework int64": {
+			in:   types.Int64Value(42),
+			want: types.Int64Value(42),
+		},
+		"configuration before env": {
+			in:   types.Int64Value(42),
+			tag:  `env:"TEST_PARSE_FIELD"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "7"},
+			want: int64(42),
+		},
+		"string from env": {
+			in:   types.StringNull(),
+			tag:  `env:"TEST_PARSE_FIELD"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "value"},
+			want: "value",
+		},
+		"bool from env": {
+			in:   types.BoolNull(),
+			tag:  `env:"TEST_PARSE_FIELD"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "true"},
+			want: true,
+		},
+		"int64 from env": {
+			in:   types.Int64Null(),
+			tag:  `env:"TEST_PARSE_FIELD"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "42"},
+			want: int64(42),
+		},
+		"framework bool from env": {
+			in:   types.BoolNull(),
+			tag:  `env:"TEST_PARSE_FIELD"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "1"},
+			want: types.BoolValue(true),
+		},
+		"framework int64 from env": {
+			in:   types.Int64Null(),
+			tag:  `env:"TEST_PARSE_FIELD"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "-1"},
+			want: types.Int64Value(-1),
+		},
+		"first env variable": {
+			in:   types.StringNull(),
+			tag:  `env:"TEST_PARSE_FIELD,TEST_PARSE_FIELD_FALLBACK"`,
+			env:  map[string]string{"TEST_PARSE_FIELD": "first", "TEST_PARSE_FIELD_FALLBACK": "second"},
+			want: "first",
+		},
+		"fallback env variable": {
+			in:   types.StringNull(),
+			tag:  `env:"TEST_PARSE_FIELD,TEST_PARSE_FIELD_FALLBACK"`,
+			env:  map[string]string{"TEST_PARSE_FIELD_FALLBACK": "second"},
+			want: "second",
+		},
+		"env override": {
+			in:          types.StringNull(),
+			tag:         `env:"TEST_PARSE_FIELD"`,
+			env:         map[string]string{"TEST_PARSE_FIELD": "default", "TEST_PARSE_FIELD_OVERRIDE": "override"},
+			envOverride: "TEST_PARSE_FIELD_OVERRIDE",
+			want:        "override",
+		},
+		"variable": {
+			in:        types.BoolNull(),
+			tag:       `env:"TEST_PARSE_FIELD"`,
+			variables: map[string]string{"TEST_PARSE_FIELD": "true"},
+			want:      true,
+		},
+		"env before variable": {
+			in:        types.StringNull(),
+			tag:       `env:"TEST_PARSE_FIELD"`,
+			env:       map[string]string{"TEST_PARSE_FIELD": "env"},
+			variables: map[string]string{"TEST_PARSE_FIELD": "variable"},
+			want:      "env",
+		},
+		"invalid bool env": {
+			in:          types.BoolNull(),
+			tag:         `env:"TEST_PARSE_FIELD"`,
+			env:         map[string]string{"TEST_PARSE_FIELD": "yes"},
+			want:        false,
+			wantSummary: "Invalid env variable",
+		},
+		"invalid int64 env": {
+			in:          types.Int64Null(),
+			tag:         `env:"TEST_PARSE_FIELD"`,
+			env:         map[string]string{"TEST_PARSE_FIELD": "4.2"},
+			want:        types.Int64{},
+			wantSummary: "Invalid env variable",
+		},
+		"invalid int64 variable": {
+			in:          types.Int64Null(),
+			tag:         `env:"TEST_PARSE_FIELD"`,
+			variables:   map[string]string{"TEST_PARSE_FIELD": "many"},
+			want:        int64(0),
+			wantSummary: "Invalid env variable",
+		},
+		"missing error": {
+			in:          types.StringNull(),
+			tag:         `env:"TEST_PARSE_FIELD" missing:"error"`,
+			want:        "",
+			wantSummary: "Missing value",
+		},
+		"missing warning": {
+			in:          types.StringNull(),
+			tag:         `missing:"warn"`,
+			want:        "",
+			wantSummary: "Missing value",
+		},
+		"missing skipped": {
+			in:          types.StringNull(),
+			tag:         `missing:"error"`,
+			skipMissing: true,
+			want:        "",
+		},
+		"unknown isn't missing": {
+			in:   types.StringUnknown(),
+			tag:  `missing:"error"`,
+			want: "",
+		},
+		"unknown framework value": {
+			in:   types.BoolUnknown(),
+			want: types.BoolUnknown(),
+		},
+		"other type copied": {
+			in:   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("value")}),
+			want: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("value")}),
+		},
+		"other type mismatch": {
+			in:          types.ListNull(types.StringType),
+			want:        "",
+			wantSummary: "Failed parsing value",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unsetEnv(t, "TEST_PARSE_FIELD", "TEST_PARSE_FIELD_FALLBACK", "TEST_PARSE_FIELD_OVERRIDE")
+			for env, value := range test.env {
+				t.Setenv(env, value)
+			}
+			field := reflect.StructField{Name: "Field", Tag: reflect.StructTag(test.tag)}
+			out := reflect.New(reflect.TypeOf(test.want)).Elem()
+			d := parseField(reflect.ValueOf(test.in), field, out, path.Root("block"), test.variables, test.envOverride, test.skipMissing)
+			if got := out.Interface(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parsed %v, want %v", got, test.want)
+			}
+			switch {
+			case d == nil && test.wantSummary != "":
+				t.Errorf("no diagnostic, want %q", test.wantSummary)
+			case d != nil && d.Summary() != test.wantSummary:
+				t.Errorf("diagnostic %q: %s, want %q", d.Summary(), d.Detail(), test.wantSummary)
+			}
+		})
+	}
+}
+
+func TestSetField(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    any
+		wantErr bool
+	}{
+		"string":           {value: "value", want: "value"},
+		"bool":             {value: "false", want: false},
+		"int64":            {value: "9007199254740993", want: int64(9007199254740993)},
+		"framework string": {value: "value", want: types.StringValue("value")},
+		"framework bool":   {value: "true", want: types.BoolValue(true)},
+		"framework int64":  {value: "42", want: types.Int64Value(42)},
+		"invalid bool":     {value: "on", want: false, wantErr: true},
+		"invalid int64":    {value: "", want: types.Int64{}, wantErr: true},
+		"unsupported type": {value: "1.5", want: float64(0), wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := reflect.New(reflect.TypeOf(test.want)).Elem()
+			err := setField(out, test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, want error %t", err, test.wantErr)
+			}
+			if got := out.Interface(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("set %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestManagedIdentityEmptyBlock(t *testing.T) {
+	tests := map[string]struct {
+		in   types.Object
+		want azidentity.ManagedIDKind
+	}{
+		"omitted block": {
+			in: types.ObjectNull(credentialObject(t, "managed_identity_credential", nil).AttributeTypes(context.Background())),
+		},
+		"empty block": {
+			in: credentialObject(t, "managed_identity_credential", nil),
+		},
+		"empty client_id": {
+			in: credentialObject(t, "managed_identity_credential", map[string]attr.Value{"client_id": types.StringValue("")}),
+		},
+		"client_id": {
+			in:   credentialObject(t, "managed_identity_credential", map[string]attr.Value{"client_id": types.StringValue(testClientID)}),
+			want: azidentity.ClientID(testClientID),
+		},
+		"resource_id": {
+			in:   credentialObject(t, "managed_identity_credential", map[string]attr.Value{"resource_id": types.StringValue("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id")}),
+			want: azidentity.ResourceID("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := diag.Diagnostics{}
+			props := parseObject[MIcM, MIcP](context.Background(), test.in, &diags, path.Root("managed_identity_credential"))
+			if diags.HasError() || props == nil {
+				t.Fatalf("parsing failed: %v", diags)
+			}
+			if got := managedIdentityID(*props); got != test.want {
+				t.Errorf("identity = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveCommonDefaultAzureCredentialClientID(t *testing.T) {
+	data := &AzIdentityProviderModel{
+		Common: credentialObject(t, "common", map[string]attr.Value{
+			"tenant_id": types.StringValue(testTenantID),
+			"client_id": types.StringValue(testClientID),
+		}),
+		DefaultAzureCredential: types.ObjectNull(credentialObject(t, "default_azure_credential", nil).AttributeTypes(context.Background())),
+		DeviceCodeCredential:   types.ObjectNull(credentialObject(t, "device_code_credential", nil).AttributeTypes(context.Background())),
+	}
+	if _, diags := resolveCommon(context.Background(), data); diags.HasError() {
+		t.Fatalf("resolveCommon: %v", diags)
+	}
+	// client_id would select the managed identity of the default Azure credential, so only tenant_id is inherited
+	attributes := data.DefaultAzureCredential.Attributes()
+	if got := attributes["tenant_id"]; !got.Equal(types.StringValue(testTenantID)) {
+		t.Errorf("default_azure_credential tenant_id = %s, want %s", got, testTenantID)
+	}
+	if got := attributes["client_id"]; !got.IsNull() {
+		t.Errorf("default_azure_credential client_id = %s, want null", got)
+	}
+	if got := data.DeviceCodeCredential.Attributes()["client_id"]; !got.Equal(types.StringValue(testClientID)) {
+		t.Errorf("device_code_credential client_id = %s, want %s", got, testClientID)
+	}
+}
+
+func TestResolveCommonConfiguredBeforeInheriting(t *testing.T) {
+	data := &AzIdentityProviderModel{
+		Common:               credentialObject(t, "common", map[string]attr.Value{"tenant_id": types.StringValue(testTenantID)}),
+		AzureCLICredential:   credentialObject(t, "azure_cli_credential", nil),
+		DeviceCodeCredential: types.ObjectNull(credentialObject(t, "device_code_credential", nil).AttributeTypes(context.Background())),
+	}
+	common, diags := resolveCommon(context.Background(), data)
+	if diags.HasError() {
+		t.Fatalf("resolveCommon: %v", diags)
+	}
+	if !credentialConfigured(data, "device_code_credential") {
+		t.Fatal("device_code_credential block wasn't created from common")
+	}
+	if common.configured["device_code_credential"] {
+		t.Error("device_code_credential is configured only by the block created from common")
+	}
+	if !common.configured["azure_cli_credential"] {
+		t.Error("azure_cli_credential block isn't configured")
+	}
+}
+
+func TestMergeAllowedTenants(t *testing.T) {
+	tests := map[string]struct {
+		configured []string
+		env        string
+		strategy   string
+		want       []string
+	}{
+		"env only":                {env: "a;b", strategy: "replace", want: []string{"a", "b"}},
+		"neither":                 {strategy: "union"},
+		"replace":                 {configured: []string{"c"}, env: "a;b", strategy: "replace", want: []string{"c"}},
+		"replace with empty list": {configured: []string{}, env: "a", strategy: "replace"},
+		"union":                   {configured: []string{"c", "a"}, env: "a; b;", strategy: "union", want: []string{"c", "a", "b"}},
+		"union without env":       {configured: []string{"c"}, strategy: "union", want: []string{"c"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := mergeAllowedTenants(test.configured, test.env, test.strategy); !slices.Equal(got, test.want) {
+				t.Errorf("tenants = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveCommonAllowedTenantsFromEnv(t *testing.T) {
+	t.Setenv(additionallyAllowedTenantsEnv, "env-tenant")
+	data := &AzIdentityProviderModel{
+		Common: credentialObject(t, "common", map[string]attr.Value{
+			"additionally_allowed_tenants":       types.ListValueMust(types.StringType, []attr.Value{types.StringValue("config-tenant")}),
+			"additionally_allowed_tenants_merge": types.StringValue("union"),
+		}),
+	}
+	common, diags := resolveCommon(context.Background(), data)
+	if diags.HasError() {
+		t.Fatalf("resolveCommon: %v", diags)
+	}
+	if want := []string{"config-tenant", "env-tenant"}; !slices.Equal(common.additionallyAllowedTenants, want) {
+		t.Errorf("tenants = %v, want %v", common.additionallyAllowedTenants, want)
+	}
+}
+
+func TestOnBehalfOfCredentialWithoutClientCredential(t *testing.T) {
+	in := credentialObject(t, "on_behalf_of_credential", map[string]attr.Value{
+		"tenant_id":      types.StringValue(testTenantID),
+		"client_id":      types.StringValue(testClientID),
+		"user_assertion": types.StringValue("user-assertion"),
+	})
+	diags := diag.Diagnostics{}
+	cred, err := newOnBehalfOfCredential(context.Background(), in, &diags, path.Root("on_behalf_of_credential"), azcore.ClientOptions{}, credentialCommon{})
+	if err != nil || cred != nil {
+		t.Errorf("credential = %v, error = %v, want neither", cred, err)
+	}
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "Missing on-behalf-of client credential" {
+		t.Errorf("errors = %v, want missing client credential", errs)
+	}
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Commands the SDK opens the browser with on Linux and BSDs, except the text mode www-browser, as the provider has no terminal.
+var browserCommands = []string{"xdg-open", "x-www-browser"}
+
+// Check whether the SDK can open a browser for the interactive browser credential. The SDK reports a failure to open
+// it as an authentication error, which stops the chain, so the credential is skipped at setup instead. Replaced in tests.
+var browserAvailable = func() error {
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		// Opened with rundll32 and open, which are part of the OS
+		return nil
+	case "linux", "freebsd", "netbsd", "openbsd":
+	default:
+		return fmt.Errorf("opening a browser isn't supported on %s", runtime.GOOS)
+	}
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return errors.New("no display to open a browser on, neither DISPLAY nor WAYLAND_DISPLAY is set")
+	}
+	for _, command := range browserCommands {
+		if _, err := exec.LookPath(command); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no command to open a browser with, install one of %s", strings.Join(browserCommands, ", "))
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Whether the credential type has a configuration block, in single or list form. Blocks created from the common block
+// count as configured, so it's checked before common values are inherited.
+func credentialConfigured(data *AzIdentityProviderModel, credentialType string) bool {
+	model := reflect.ValueOf(data).Elem()
+	for i := range model.NumField() {
+		name := model.Type().Field(i).Tag.Get("tfsdk")
+		if name != credentialType && name != credentialType+"s" {
+			continue
+		}
+		switch value := model.Field(i).Interface().(type) {
+		case types.Object:
+			if !value.IsNull() {
+				return true
+			}
+		case types.List:
+			if len(value.Elements()) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Whether any env variable the credential type detects its environment from is set.
+func credentialEnvFound(credentialType string) bool {
+	for _, env := range credentialOrderEnvs[credentialType] {
+		if _, ok := os.LookupEnv(env); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Log a step of the credential chain trace, in the order credentials are set up. Only names and booleans are
+// logged, never configuration values or errors, as they may contain secrets.
+func traceCredential(ctx context.Context, credentialType string, label string, position int, configured bool, constructed bool) {
+	tflog.Info(ctx, fmt.Sprintf("Credential chain step %d: %s", position, label), map[string]interface{}{
+		"chain_position":   position,
+		"credential_label": label,
+		"config_found":     configured,
+		"env_found":        credentialEnvFound(credentialType),
+		"setup_succeeded":  constructed,
+	})
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Merge claims request JSON documents (ex. `{"access_token":{"nbf":{"essential":true,"value":"1700000000"}}}`) into one.
+// Objects are merged recursively, `values` lists are combined without duplicates and other values of later documents win.
+func mergeClaims(claims []string) (string, error) {
+	merged := map[string]any{}
+	for _, claim := range claims {
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(claim), &doc); err != nil {
+			return "", fmt.Errorf("claims '%s' aren't a valid JSON object: %w", claim, err)
+		}
+		mergeClaimsObject(merged, doc)
+	}
+	if len(merged) == 0 {
+		return "", nil
+	}
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func mergeClaimsObject(dst map[string]any, src map[string]any) {
+	for key, value := range src {
+		switch value := value.(type) {
+		case map[string]any:
+			if existing, ok := dst[key].(map[string]any); ok {
+				mergeClaimsObject(existing, value)
+				continue
+			}
+		case []any:
+			if existing, ok := dst[key].([]any); ok && key == "values" {
+				for _, v := range value {
+					if !slices.Contains(existing, v) {
+						existing = append(existing, v)
+					}
+				}
+				dst[key] = existing
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// Header with the client request ID, set on every request of the credentials.
+const clientRequestIDHeader = "x-ms-client-request-id"
+
+var _ policy.Policy = correlationPolicy{}
+
+// correlationPolicy sets the same client request ID on all requests of a run, so they can be correlated in Entra ID
+// sign-in logs and support tickets.
+type correlationPolicy struct {
+	id string
+}
+
+func (p correlationPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.Raw().Header.Set(clientRequestIDHeader, p.id)
+	return req.Next()
+}
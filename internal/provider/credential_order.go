@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// Environment variables indicating the credential has everything it needs in the current environment.
+var credentialOrderEnvs = map[string][]string{
+	"environment_credential":       {"AZURE_CLIENT_SECRET", "AZURE_CLIENT_CERTIFICATE_PATH", "AZURE_USERNAME"},
+	"workload_identity_credential": {"AZURE_FEDERATED_TOKEN_FILE"},
+	"azure_pipelines_credential":   {"SYSTEM_OIDCREQUESTURI"},
+	"github_oidc_credential":       {githubOIDCRequestURLEnv},
+}
+
+// Estimate how fast a credential gets a token, lower is faster.
+//   - 0: credentials configured explicitly or detected from environment variables, only doing a single token request
+//   - 1: environment based credentials without their environment variables, which fail fast
+//   - 2: azure_cli_credential and azure_developer_cli_credential, which start a subprocess
+//   - 3: managed_identity_credential, which may wait for the IMDS endpoint to time out outside of Azure, and
+//     default_azure_credential, which may include it
+//   - 4: interactive credentials, which wait for the user to sign in
+func credentialOrderScore(name string) int {
+	if slices.Contains(interactiveCredentialTypes, name) {
+		return 4
+	}
+	switch name {
+	case "azure_cli_credential", "azure_developer_cli_credential":
+		return 2
+	case "managed_identity_credential", "default_azure_credential":
+		return 3
+	}
+	envs, ok := credentialOrderEnvs[name]
+	if !ok {
+		return 0
+	}
+	for _, env := range envs {
+		if _, ok := os.LookupEnv(env); ok {
+			return 0
+		}
+	}
+	return 1
+}
+
+func credentialName(credential azcore.TokenCredential) string {
+	if recording, ok := credential.(*recordingCredential); ok {
+		return recording.name
+	}
+	return ""
+}
+
+// Reorder credentials so the fastest are tried first. Order of credentials with the same score is preserved.
+func optimizeCredentialOrder(credentials []azcore.TokenCredential) {
+	sort.SliceStable(credentials, func(i, j int) bool {
+		return credentialOrderScore(credentialName(credentials[i])) < credentialOrderScore(credentialName(credentials[j]))
+	})
+}
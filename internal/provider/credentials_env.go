@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Env variable with a comma separated list of credential types, overriding `credentials` when
+// `credentials_from_env` is set.
+const credentialsEnv = "AZIDENTITY_CREDENTIALS"
+
+// Get credential types from the env variable, validated like the `credentials` attribute. False when it's not set.
+func credentialsFromEnv() ([]types.String, bool, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	value, ok := os.LookupEnv(credentialsEnv)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, false, diags
+	}
+	out := []types.String{}
+	for _, c := range strings.Split(value, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !slices.Contains(credentialTypes, c) {
+			diags.AddAttributeError(path.Root("credentials_from_env"), "Invalid credential type", fmt.Sprintf("%s contains '%s', which isn't one of: %s.", credentialsEnv, c, strings.Join(credentialTypes, ", ")))
+			continue
+		}
+		if slices.Contains(out, types.StringValue(c)) {
+			diags.AddAttributeError(path.Root("credentials_from_env"), "Duplicate credential type", fmt.Sprintf("%s contains '%s' more than once.", credentialsEnv, c))
+			continue
+		}
+		out = append(out, types.StringValue(c))
+	}
+	return out, true, diags
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ChainInfoDataSource{}
+var _ datasource.DataSourceWithConfigure = &ChainInfoDataSource{}
+
+func NewChainInfoDataSource() datasource.DataSource {
+	return &ChainInfoDataSource{}
+}
+
+// ChainInfoDataSource defines the data source implementation.
+type ChainInfoDataSource struct {
+	providerData *AzIdentityProviderData
+}
+
+// ChainInfoDataSourceModel describes the data source data model.
+type ChainInfoDataSourceModel struct {
+	Credentials []ChainInfoCredentialModel `tfsdk:"credentials"`
+	Order       []types.String             `tfsdk:"order"`
+}
+
+// ChainInfoCredentialModel describes a single configured credential.
+type ChainInfoCredentialModel struct {
+	Type        types.String `tfsdk:"type"`
+	Label       types.String `tfsdk:"label"`
+	Constructed types.Bool   `tfsdk:"constructed"`
+	Error       types.String `tfsdk:"error"`
+}
+
+func (d *ChainInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_chain_info"
+}
+
+func (d *ChainInfoDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Describes the credential chain configured in the provider, ex. for documentation or compliance reporting. No tokens are requested.",
+		Attributes: map[string]schema.Attribute{
+			"credentials": schema.ListNestedAttribute{
+				MarkdownDescription: "Configured credentials in the order of the provider `credentials` list, with credentials configured in list form expanded",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Credential type, ex. `azure_cli_credential`",
+							Computed:            true,
+						},
+						"label": schema.StringAttribute{
+							MarkdownDescription: "Label of the credential, ex. `client_secret_credentials[0]` for credentials configured in list form",
+							Computed:            true,
+						},
+						"constructed": schema.BoolAttribute{
+							MarkdownDescription: "Whether the credential was set up and added to the chain",
+							Computed:            true,
+						},
+						"error": schema.StringAttribute{
+							MarkdownDescription: "Error setting up the credential, null when there was none",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"order": schema.ListAttribute{
+				MarkdownDescription: "Types of credentials in the chain in the order they're tried, after `optimize_order` is applied",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ChainInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Always perform a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*AzIdentityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *AzIdentityProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *ChainInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Provider configuration isn't known yet, the chain is only set up once it is
+	if d.providerData == nil || d.providerData.Credential == nil {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &ChainInfoDataSourceModel{})...)
+		return
+	}
+
+	data := ChainInfoDataSourceModel{
+		Credentials: make([]ChainInfoCredentialModel, 0, len(d.providerData.CredentialSetup)),
+		Order:       make([]types.String, 0, len(d.providerData.CredentialTypes)),
+	}
+	for _, setup := range d.providerData.CredentialSetup {
+		credential := ChainInfoCredentialModel{
+			Type:        types.StringValue(setup.Type),
+			Label:       types.StringValue(setup.Label),
+			Constructed: types.BoolValue(setup.Constructed),
+			Error:       types.StringNull(),
+		}
+		if setup.Error != "" {
+			credential.Error = types.StringValue(setup.Error)
+		}
+		data.Credentials = append(data.Credentials, credential)
+	}
+	for _, credentialType := range d.providerData.CredentialTypes {
+		data.Order = append(data.Order, types.StringValue(credentialType))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
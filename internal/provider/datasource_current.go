@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CurrentDataSource{}
+var _ datasource.DataSourceWithConfigure = &CurrentDataSource{}
+
+// Microsoft Graph endpoints by cloud name. Custom clouds use the global endpoint.
+var graphEndpoints = map[string]string{
+	"AzurePublic":     "https://graph.microsoft.com",
+	"AzureGovernment": "https://graph.microsoft.us",
+	"AzureChina":      "https://microsoftgraph.chinacloudapi.cn",
+}
+
+func NewCurrentDataSource() datasource.DataSource {
+	return &CurrentDataSource{}
+}
+
+// CurrentDataSource defines the data source implementation.
+type CurrentDataSource struct {
+	providerData *AzIdentityProviderData
+}
+
+// CurrentDataSourceModel describes the data source data model.
+type CurrentDataSourceModel struct {
+	ObjectID    types.String `tfsdk:"object_id"`
+	TenantID    types.String `tfsdk:"tenant_id"`
+	ClientID    types.String `tfsdk:"client_id"`
+	DisplayName types.String `tfsdk:"display_name"`
+}
+
+// Directory object returned by Microsoft Graph.
+type graphObject struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+func (d *CurrentDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current"
+}
+
+func (d *CurrentDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Identity of the principal the provider authenticates as, ex. for role assignments of the deploying principal. A Microsoft Graph token is requested with the provider's credential chain. IDs come from its claims, and the display name from Graph `/me` (users) or `/servicePrincipals` (applications and managed identities).",
+		Attributes: map[string]schema.Attribute{
+			"object_id": schema.StringAttribute{
+				MarkdownDescription: "Object ID of the user or service principal",
+				Computed:            true,
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant the token was issued by",
+				Computed:            true,
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "Client ID of the application the token was issued to, ex. Azure CLI for users signed in with `az login`",
+				Computed:            true,
+			},
+			"display_name": schema.StringAttribute{
+				MarkdownDescription: "Display name of the user or service principal. Null with a warning when Graph can't be read, ex. when the application has no permission to read its service principal.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *CurrentDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Always perform a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*AzIdentityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *AzIdentityProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *CurrentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Provider configuration isn't known yet, the chain is only set up once it is
+	if d.providerData == nil || d.providerData.Credential == nil {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &CurrentDataSourceModel{
+			ObjectID:    types.StringUnknown(),
+			TenantID:    types.StringUnknown(),
+			ClientID:    types.StringUnknown(),
+			DisplayName: types.StringUnknown(),
+		})...)
+		return
+	}
+
+	graph, ok := graphEndpoints[d.providerData.CloudName]
+	if !ok {
+		graph = graphEndpoints["AzurePublic"]
+	}
+	attempts := &credentialAttempts{}
+	token, err := d.providerData.Credential.GetToken(withCredentialAttempts(ctx, attempts), policy.TokenRequestOptions{Scopes: []string{graph + "/.default"}})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to get Microsoft Graph token", attempts.failureSummary(err))
+		return
+	}
+	claims, err := decodeTokenClaims(token.Token)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read identity from token", err.Error())
+		return
+	}
+	claim := func(names ...string) types.String {
+		for _, name := range names {
+			if value, ok := claims[name].(string); ok && value != "" {
+				return types.StringValue(value)
+			}
+		}
+		return types.StringNull()
+	}
+	data := CurrentDataSourceModel{
+		ObjectID: claim("oid"),
+		TenantID: claim("tid"),
+		// v1 tokens have appid, v2 tokens azp
+		ClientID:    claim("appid", "azp"),
+		DisplayName: types.StringNull(),
+	}
+
+	// Delegated tokens have scp, app-only tokens roles
+	objectURL := graph + "/v1.0/me?$select=id,displayName"
+	if _, delegated := claims["scp"]; !delegated {
+		objectURL = fmt.Sprintf("%s/v1.0/servicePrincipals(appId='%s')?$select=id,displayName", graph, url.PathEscape(data.ClientID.ValueString()))
+	}
+	if object, err := d.getGraphObject(ctx, objectURL, token.Token); err != nil {
+		resp.Diagnostics.AddAttributeWarning(path.Root("display_name"), "Unable to read identity from Microsoft Graph", fmt.Sprintf("Display name isn't available: %s", err))
+	} else {
+		data.DisplayName = types.StringValue(object.DisplayName)
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Get a directory object from Microsoft Graph, using the transport of token requests.
+func (d *CurrentDataSource) getGraphObject(ctx context.Context, objectURL string, token string) (*graphObject, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var transport policy.Transporter = http.DefaultClient
+	if d.providerData.Transport != nil {
+		transport = d.providerData.Transport
+	}
+	resp, err := transport.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed requesting Microsoft Graph: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading Microsoft Graph response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Microsoft Graph returned %s: %s", resp.Status, string(body))
+	}
+	object := &graphObject{}
+	if err := json.Unmarshal(body, object); err != nil {
+		return nil, fmt.Errorf("failed parsing Microsoft Graph response: %w", err)
+	}
+	return object, nil
+}
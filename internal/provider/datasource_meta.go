@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MetaDataSource{}
+var _ datasource.DataSourceWithConfigure = &MetaDataSource{}
+
+func NewMetaDataSource() datasource.DataSource {
+	return &MetaDataSource{}
+}
+
+// MetaDataSource defines the data source implementation.
+type MetaDataSource struct {
+	providerData *AzIdentityProviderData
+}
+
+// MetaDataSourceModel describes the data source data model.
+type MetaDataSourceModel struct {
+	Version         types.String `tfsdk:"version"`
+	CredentialTypes types.List   `tfsdk:"credential_types"`
+	Clouds          types.List   `tfsdk:"clouds"`
+	Cloud           types.String `tfsdk:"cloud"`
+	LastHealthCheck types.String `tfsdk:"last_health_check"`
+	HealthError     types.String `tfsdk:"health_check_error"`
+}
+
+func (d *MetaDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_meta"
+}
+
+func (d *MetaDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Exposes the provider version and capabilities, so modules can assert them in preconditions.",
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Version of the provider, `dev` for local builds",
+				Computed:            true,
+			},
+			"credential_types": schema.ListAttribute{
+				MarkdownDescription: "Credential types supported in the provider `credentials` list",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"clouds": schema.ListAttribute{
+				MarkdownDescription: "Cloud names supported in the provider `cloud` attribute",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"cloud": schema.StringAttribute{
+				MarkdownDescription: "Cloud the provider resolved from the `cloud` attribute, ex. `AzurePublic` when it's not set or not recognized. Null when the provider configuration isn't known yet.",
+				Computed:            true,
+			},
+			"last_health_check": schema.StringAttribute{
+				MarkdownDescription: "Time of the last background credential health check in RFC 3339 format. Null when `health_check_interval` isn't set or no check has completed yet.",
+				Computed:            true,
+			},
+			"health_check_error": schema.StringAttribute{
+				MarkdownDescription: "Error of the last background credential health check, null when it succeeded or no check has completed yet.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *MetaDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Always perform a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*AzIdentityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *AzIdentityProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *MetaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data MetaDataSourceModel
+	var diags diag.Diagnostics
+
+	data.Version = types.StringNull()
+	data.Cloud = types.StringNull()
+	data.LastHealthCheck = types.StringNull()
+	data.HealthError = types.StringNull()
+	if d.providerData != nil {
+		data.Version = types.StringValue(d.providerData.Version)
+		if d.providerData.CloudName != "" {
+			data.Cloud = types.StringValue(d.providerData.CloudName)
+		}
+		if d.providerData.HealthChecker != nil {
+			if checked, err := d.providerData.HealthChecker.lastResult(); !checked.IsZero() {
+				data.LastHealthCheck = types.StringValue(checked.Format(time.RFC3339))
+				if err != "" {
+					data.HealthError = types.StringValue(err)
+				}
+			}
+		}
+	}
+	data.CredentialTypes, diags = types.ListValueFrom(ctx, types.StringType, credentialTypes)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Clouds, diags = types.ListValueFrom(ctx, types.StringType, cloudNames)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
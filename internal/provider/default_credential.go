@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Build the chain of DefaultAzureCredential by hand, as the SDK doesn't allow excluding its sources. Sources are
+// added in the same order as in the SDK, and IMDS is probed before the first managed identity request like in the SDK.
+func newDefaultAzureCredential(ctx context.Context, in types.Object, diags *diag.Diagnostics, p path.Path, clientOptions azcore.ClientOptions, common credentialCommon) (azcore.TokenCredential, error) {
+	var props DefaultAzureCredentialModel
+	if !in.IsNull() && !in.IsUnknown() {
+		if newDiags := in.As(ctx, &props, basetypes.ObjectAsOptions{}); newDiags.HasError() {
+			diags.Append(newDiags...)
+			return nil, nil
+		}
+	}
+	tenantID := props.TenantID.ValueString()
+	// Like in the SDK, AZURE_CLIENT_ID selects the application and managed identity when client_id isn't set
+	clientID := props.ClientID.ValueString()
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+
+	sources := []azcore.TokenCredential{}
+	errs := []error{}
+	add := func(name string, cred azcore.TokenCredential, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		} else {
+			sources = append(sources, cred)
+		}
+	}
+	if !props.ExcludeEnvironment.ValueBool() {
+		cred, err := azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{ClientOptions: clientOptions, DisableInstanceDiscovery: common.disableInstanceDiscovery})
+		add("environment", cred, err)
+	}
+	if !props.ExcludeWorkloadIdentity.ValueBool() {
+		cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions:              clientOptions,
+			TenantID:                   tenantID,
+			ClientID:                   clientID,
+			AdditionallyAllowedTenants: common.additionallyAllowedTenants,
+			DisableInstanceDiscovery:   common.disableInstanceDiscovery,
+			Cache:                      common.cache,
+		})
+		add("workload identity", cred, err)
+	}
+	if !props.ExcludeManagedIdentity.ValueBool() {
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if clientID != "" {
+			options.ID = azidentity.ClientID(clientID)
+		}
+		cred, err := azidentity.NewManagedIdentityCredential(options)
+		if err == nil && imdsSource() {
+			add("managed identity", &imdsProbeCredential{credential: cred, transport: clientOptions.Transport}, nil)
+		} else {
+			add("managed identity", cred, err)
+		}
+	}
+	if !props.ExcludeCLI.ValueBool() {
+		cred, err := azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{TenantID: tenantID, AdditionallyAllowedTenants: common.additionallyAllowedTenants})
+		add("azure cli", cred, err)
+	}
+	if !props.ExcludeDeveloperCLI.ValueBool() {
+		cred, err := azidentity.NewAzureDeveloperCLICredential(&azidentity.AzureDeveloperCLICredentialOptions{TenantID: tenantID, AdditionallyAllowedTenants: common.additionallyAllowedTenants})
+		add("azure developer cli", cred, err)
+	}
+	if !props.ExcludePowerShell.ValueBool() {
+		cred, err := azidentity.NewAzurePowerShellCredential(&azidentity.AzurePowerShellCredentialOptions{TenantID: tenantID, AdditionallyAllowedTenants: common.additionallyAllowedTenants})
+		add("azure powershell", cred, err)
+	}
+
+	if len(sources) == 0 {
+		if len(errs) == 0 {
+			diags.AddAttributeWarning(p, "All sources excluded", "All sources of default_azure_credential are excluded, so it's not added to the chain.")
+			return nil, nil
+		}
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		diags.AddAttributeWarning(p, "Error setting up default_azure_credential source", err.Error())
+	}
+	return azidentity.NewChainedTokenCredential(sources, nil)
+}
+
+// IMDS token endpoint, probed before the first token request of the managed identity source. Replaced in tests.
+var imdsEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+const imdsProbeTimeout = time.Second
+
+// Whether managed identity gets tokens from IMDS, as no env variable of another managed identity environment
+// (App Service, Azure Arc, Service Fabric or Cloud Shell) is set.
+func imdsSource() bool {
+	for _, env := range []string{"IDENTITY_ENDPOINT", "MSI_ENDPOINT"} {
+		if _, ok := os.LookupEnv(env); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// imdsProbeCredential probes IMDS with a short timeout before the first token request, like DefaultAzureCredential of
+// the SDK does, which can't be set on a managed identity credential built by hand. Outside Azure, IMDS requests wait
+// for the retries of the SDK, so the chain would stall before trying developer tool credentials.
+type imdsProbeCredential struct {
+	credential azcore.TokenCredential
+	// Transport of the client options, nil for the default
+	transport policy.Transporter
+	mu        sync.Mutex
+	probed    bool
+}
+
+// GetToken reports the credential unavailable when IMDS doesn't respond, so the chain moves on. IMDS is probed until
+// it responds once, any response shows it's there.
+func (c *imdsProbeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.mu.Lock()
+	if !c.probed {
+		if err := c.probe(ctx); err != nil {
+			c.mu.Unlock()
+			return azcore.AccessToken{}, azidentity.NewCredentialUnavailableError(fmt.Sprintf("ManagedIdentityCredential: no response from IMDS within %s: %s", imdsProbeTimeout, err))
+		}
+		c.probed = true
+	}
+	c.mu.Unlock()
+	return c.credential.GetToken(ctx, opts)
+}
+
+// Send a request without the Metadata header, which IMDS rejects without issuing a token.
+func (c *imdsProbeCredential) probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, imdsProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imdsEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	var resp *http.Response
+	if c.transport != nil {
+		resp, err = c.transport.Do(req)
+	} else {
+		resp, err = http.DefaultClient.Do(req)
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// Credential returning a fixed token, counting requests.
+type staticCredential struct {
+	token    string
+	requests int
+}
+
+func (c *staticCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.requests++
+	return azcore.AccessToken{Token: c.token}, nil
+}
+
+// Chain of the probed managed identity and a fallback credential, with IMDS at the endpoint.
+func imdsProbeChain(t *testing.T, endpoint string) (*azidentity.ChainedTokenCredential, *staticCredential, *staticCredential) {
+	t.Helper()
+	original := imdsEndpoint
+	imdsEndpoint = endpoint
+	t.Cleanup(func() { imdsEndpoint = original })
+	managedIdentity := &staticCredential{token: "managed identity"}
+	fallback := &staticCredential{token: "fallback"}
+	chain, err := azidentity.NewChainedTokenCredential([]azcore.TokenCredential{&imdsProbeCredential{credential: managedIdentity}, fallback}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain, managedIdentity, fallback
+}
+
+func TestIMDSProbeResponding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// IMDS rejects requests without the Metadata header
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+	chain, managedIdentity, _ := imdsProbeChain(t, server.URL)
+	for range 2 {
+		token, err := chain.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com/.default"}})
+		if err != nil || token.Token != "managed identity" {
+			t.Fatalf("token = %q, error = %v, want the managed identity token", token.Token, err)
+		}
+	}
+	if managedIdentity.requests != 2 {
+		t.Errorf("managed identity requests = %d, want 2", managedIdentity.requests)
+	}
+}
+
+func TestIMDSProbeUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	chain, managedIdentity, _ := imdsProbeChain(t, server.URL)
+	token, err := chain.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com/.default"}})
+	if err != nil || token.Token != "fallback" {
+		t.Fatalf("token = %q, error = %v, want the fallback token", token.Token, err)
+	}
+	if managedIdentity.requests != 0 {
+		t.Errorf("managed identity requests = %d, want none without IMDS", managedIdentity.requests)
+	}
+}
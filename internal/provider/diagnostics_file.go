@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Sensitive attributes of credential blocks, their values are redacted from the diagnostics file.
+var sensitiveAttributes = []string{"client_secret", "certificate_pem", "certificate_base64", "certificate_password", "system_access_token", "token", "assertion", "password", "user_assertion"}
+
+// Env variables with secrets read by credentials, their values are redacted from the diagnostics file.
+var sensitiveEnvs = []string{"AZURE_CLIENT_SECRET", "ARM_CLIENT_SECRET", "AZURE_CLIENT_CERTIFICATE_PASSWORD", "ARM_CLIENT_CERTIFICATE_PASSWORD", "AZURE_PASSWORD", "ARM_OIDC_REQUEST_TOKEN", "SYSTEM_ACCESSTOKEN", "ACTIONS_ID_TOKEN_REQUEST_TOKEN"}
+
+// JWTs (access tokens, OIDC tokens) which may be part of error messages.
+var jwtRegex = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+const redacted = "[REDACTED]"
+
+type diagnosticsFileEntry struct {
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Summary   string `json:"summary"`
+	Detail    string `json:"detail,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+}
+
+// Appends diagnostics to a file as JSON lines, with secrets redacted.
+type diagnosticsFile struct {
+	mu      sync.Mutex
+	file    string
+	secrets []string
+}
+
+// Create a diagnostics file writer, redacting sensitive values of the provider configuration and env variables.
+func newDiagnosticsFile(file string, data *AzIdentityProviderModel) *diagnosticsFile {
+	secrets := []string{}
+	for _, env := range sensitiveEnvs {
+		if value := os.Getenv(env); value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+	// Credential blocks are objects or lists of objects in the provider model
+	model := reflect.ValueOf(data).Elem()
+	for i := range model.NumField() {
+		switch value := model.Field(i).Interface().(type) {
+		case types.Object:
+			secrets = append(secrets, objectSecrets(value)...)
+		case types.List:
+			for _, element := range value.Elements() {
+				if object, ok := element.(types.Object); ok {
+					secrets = append(secrets, objectSecrets(object)...)
+				}
+			}
+		}
+	}
+	return &diagnosticsFile{file: file, secrets: secrets}
+}
+
+// Get values of sensitive attributes of the object.
+func objectSecrets(object types.Object) []string {
+	secrets := []string{}
+	for name, value := range object.Attributes() {
+		if str, ok := value.(types.String); ok && slices.Contains(sensitiveAttributes, name) && str.ValueString() != "" {
+			secrets = append(secrets, str.ValueString())
+		}
+	}
+	return secrets
+}
+
+func (f *diagnosticsFile) redact(s string) string {
+	for _, secret := range f.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return jwtRegex.ReplaceAllString(s, redacted)
+}
+
+// Append the diagnostics produced by the source (ex. `configure` or `azidentity_token`). Safe to call on nil.
+func (f *diagnosticsFile) write(source string, diags diag.Diagnostics) error {
+	if f == nil || len(diags) == 0 {
+		return nil
+	}
+	lines := strings.Builder{}
+	for _, d := range diags {
+		entry := diagnosticsFileEntry{
+			Source:   source,
+			Severity: strings.ToLower(d.Severity().String()),
+			Summary:  f.redact(d.Summary()),
+			Detail:   f.redact(d.Detail()),
+		}
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			entry.Attribute = withPath.Path().String()
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines.Write(line)
+		lines.WriteByte('\n')
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out, err := os.OpenFile(f.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed opening diagnostics file: %w", err)
+	}
+	defer out.Close()
+	_, err = out.WriteString(lines.String())
+	return err
+}
@@ -2,17 +2,37 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	internalvalidator "github.com/rikpat/terraform-provider-azidentity/internal/validator"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ ephemeral.EphemeralResource = &TokenEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &TokenEphemeralResource{}
 
 func NewTokenEphemeralResource() ephemeral.EphemeralResource {
 	return &TokenEphemeralResource{}
@@ -20,17 +40,74 @@ func NewTokenEphemeralResource() ephemeral.EphemeralResource {
 
 // TokenEphemeralResource defines the ephemeral resource implementation.
 type TokenEphemeralResource struct {
-	credential *azidentity.ChainedTokenCredential
+	credential      *azidentity.ChainedTokenCredential
+	credentialTypes []string
+	cloudName       string
+	cloud           cloud.Configuration
+	allowedScopes   []string
+	defaultScopes   []string
+	scopeRequests   *scopeRequestCounter
+	tokenCache      *tokenCache
+	chainRetries    int64
+	chainRetryDelay time.Duration
+	diagnosticsFile *diagnosticsFile
 }
 
+// Token modes each credential type can issue. Credentials signing in a user issue delegated tokens,
+// applications and managed identities issue app-only tokens.
+var credentialTokenModes = map[string][]string{
+	"environment_credential":         {"app", "delegated"},
+	"azure_pipelines_credential":     {"app"},
+	"workload_identity_credential":   {"app"},
+	"managed_identity_credential":    {"app"},
+	"azure_cli_credential":           {"app", "delegated"},
+	"client_secret_credential":       {"app"},
+	"client_certificate_credential":  {"app"},
+	"github_oidc_credential":         {"app"},
+	"default_azure_credential":       {"app", "delegated"},
+	"device_code_credential":         {"delegated"},
+	"interactive_browser_credential": {"delegated"},
+	"client_assertion_credential":    {"app"},
+	"username_password_credential":   {"delegated"},
+	"azure_developer_cli_credential": {"app", "delegated"},
+	"on_behalf_of_credential":        {"delegated"},
+}
+
+// Whether the SDK can request a token lifetime. Tokens are checked against the requested lifetime until it can.
+const requestedLifetimeSupported = false
+
 // TokenEphemeralResourceModel describes the ephemeral resource data model.
 type TokenEphemeralResourceModel struct {
 	// Output
-	Token types.String `tfsdk:"token"`
+	Token               types.String  `tfsdk:"token"`
+	Dotenv              types.String  `tfsdk:"dotenv"`
+	TokenWithPrefix     types.String  `tfsdk:"token_with_prefix"`
+	AuthorizationHeader types.String  `tfsdk:"authorization_header"`
+	ExpiresOn           types.String  `tfsdk:"expires_on"`
+	ExpiresInSeconds    types.Int64   `tfsdk:"expires_in_seconds"`
+	ExpiresOnRaw        types.String  `tfsdk:"expires_on_raw"`
+	AppRoles            types.List    `tfsdk:"app_roles"`
+	Decoded             types.Dynamic `tfsdk:"decoded"`
+	ClaimsJSON          types.String  `tfsdk:"claims_json"`
+	ExecCredentialJSON  types.String  `tfsdk:"exec_credential_json"`
+	SourceCredential    types.String  `tfsdk:"source_credential"`
+	ResolvedTenantID    types.String  `tfsdk:"resolved_tenant_id"`
 	// Inputs
-	Claims    types.String `tfsdk:"claims"`
-	EnableCAE types.Bool   `tfsdk:"enable_cae"`
-	Scopes    types.Set    `tfsdk:"scopes"`
+	Claims            types.String `tfsdk:"claims"`
+	MergeClaims       types.List   `tfsdk:"merge_claims"`
+	EnableCAE         types.Bool   `tfsdk:"enable_cae"`
+	Scopes            types.Set    `tfsdk:"scopes"`
+	CloudScopes       types.Map    `tfsdk:"cloud_scopes"`
+	DotenvVariable    types.String `tfsdk:"dotenv_variable"`
+	TokenMode         types.String `tfsdk:"token_mode"`
+	SummaryFile       types.String `tfsdk:"summary_file"`
+	RequestedLifetime types.String `tfsdk:"requested_lifetime"`
+	ExpiryMargin      types.String `tfsdk:"expiry_margin"`
+	SummaryFields     types.Set    `tfsdk:"summary_fields"`
+	ExecCredential    types.Bool   `tfsdk:"exec_credential"`
+	TokenPrefix       types.String `tfsdk:"token_prefix"`
+	TenantID          types.String `tfsdk:"tenant_id"`
+	Timeout           types.String `tfsdk:"timeout"`
 }
 
 func (r *TokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
@@ -45,20 +122,162 @@ func (r *TokenEphemeralResource) Schema(ctx context.Context, _ ephemeral.SchemaR
 				Description: "Any additional claims required for the token to satisfy a conditional access policy, such as a service may return in a claims challenge following an authorization failure.",
 				Optional:    true,
 			},
+			"merge_claims": schema.ListAttribute{
+				MarkdownDescription: "List of claims request JSON documents merged into a single claims request together with `claims`, ex. claims required by the application and claims returned in a CAE challenge, when both need to be satisfied. Nested objects are merged, `values` lists are combined and later documents take precedence for other values.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
 			"enable_cae": schema.BoolAttribute{
 				Description: "Indicates whether to enable Continuous Access Evaluation (CAE) for the requested token. Requires a client supporting CAE. The default is false.",
 				Optional:    true,
 			},
 			"scopes": schema.SetAttribute{
-				MarkdownDescription: "List of permission scopes required for the token, ex. `https://ossrdbms-aad.database.windows.net/.default` for relational databases. Although a list is supported, it's probably better to use separate tokens for separate scopes.",
-				Required:            true,
+				MarkdownDescription: "List of permission scopes required for the token, ex. `https://ossrdbms-aad.database.windows.net/.default` for relational databases. Although a list is supported, it's probably better to use separate tokens for separate scopes. Aliases `sql` (Azure SQL) and `postgres`/`mysql` (Azure Database for PostgreSQL/MySQL) are replaced with the database scope of the provider's cloud. Conflicts with `cloud_scopes`. When neither is set, provider `default_scopes` are used, and it's an error if there are none.",
+				Optional:            true,
 				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ConflictsWith(path.MatchRoot("cloud_scopes")),
+					setvalidator.ValueStringsAre(internalvalidator.Scope()),
+				},
+			},
+			"cloud_scopes": schema.MapAttribute{
+				MarkdownDescription: "Scopes keyed by cloud name (" + strings.Join(cloudNames, ", ") + "), for modules used with multiple clouds. Scopes of the provider's cloud are used, falling back to the `default` key. It's an error if neither is present. Aliases are replaced as in `scopes`.",
+				Optional:            true,
+				ElementType:         types.SetType{ElemType: types.StringType},
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.OneOf(append(slices.Clone(cloudNames), "default")...)),
+					mapvalidator.ValueSetsAre(setvalidator.ValueStringsAre(internalvalidator.Scope())),
+				},
 			},
 			"token": schema.StringAttribute{
 				Description: "Output token for required scopes",
 				Computed:    true,
 				Sensitive:   true,
 			},
+			"token_prefix": schema.StringAttribute{
+				MarkdownDescription: "Authentication scheme prepended to the token in `token_with_prefix`, separated by a space, ex. `Bearer` for Authorization headers. Case is preserved, as some consumers expect `bearer`. Empty by default, making `token_with_prefix` equal to `token`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._+-]*$`), "must be an authentication scheme, ex. Bearer"),
+				},
+			},
+			"token_with_prefix": schema.StringAttribute{
+				MarkdownDescription: "Token prefixed with `token_prefix`, ex. `Bearer <token>`",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"authorization_header": schema.StringAttribute{
+				MarkdownDescription: "Value of an `Authorization` header, `Bearer <token>`, regardless of `token_prefix`",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant to request the token from instead of the tenant of the credential, ex. a tenant the application or user is a guest in. The tenant must be allowed by `additionally_allowed_tenants` in the provider `common` block, otherwise the request fails.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"token_mode": schema.StringAttribute{
+				MarkdownDescription: "Expected kind of token, *app* (app-only token of an application or managed identity) or *delegated* (token of a signed in user). When set, it's an error if none of the configured credentials can issue this kind of token, or if the token was issued by a credential which can't.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("app", "delegated"),
+				},
+			},
+			"requested_lifetime": schema.StringAttribute{
+				MarkdownDescription: "Requested lifetime of the token, as a duration (ex. `30m`). Currently the Azure SDK can't request a token lifetime, as Entra ID sets lifetimes centrally with token lifetime policies. A warning with the actual lifetime is shown when it's longer than requested.",
+				Optional:            true,
+				Validators: []validator.String{
+					internalvalidator.Duration(),
+				},
+			},
+			"expiry_margin": schema.StringAttribute{
+				MarkdownDescription: "Safety margin subtracted from the expiry reported in `expires_on` and `expires_in_seconds`, as a duration (ex. `5m`), so rotation logic refreshes the token before it actually expires. Only the reported values are affected, the token itself is unchanged and stays valid until its actual expiry (`expires_on_raw` isn't adjusted).",
+				Optional:            true,
+				Validators: []validator.String{
+					internalvalidator.Duration(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time to wait for the token, as a duration (ex. `30s`), including retries of the credential chain. It's an error when the token isn't issued in time. No timeout by default.",
+				Optional:            true,
+				Validators: []validator.String{
+					internalvalidator.Duration(),
+				},
+			},
+			"summary_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a JSON file the token summary is written to, for audit tools verifying the token after Terraform runs. The raw token is never written, only the fields in `summary_fields`. The file is readable only by the current user and removed when the token is closed.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"summary_fields": schema.SetAttribute{
+				MarkdownDescription: "Fields written to `summary_file`: *credential* (type of credential which issued the token), *scopes*, *expires_on* (RFC 3339 in UTC) and *fingerprint* (SHA-256 of the token). Defaults to all of them.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(tokenSummaryFields...)),
+					setvalidator.AlsoRequires(path.MatchRoot("summary_file")),
+				},
+			},
+			"dotenv_variable": schema.StringAttribute{
+				MarkdownDescription: "Name of the environment variable used in `dotenv` output, ex. `AZURE_ACCESS_TOKEN`. The `dotenv` output is only populated when this is set.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`), "must be a valid environment variable name"),
+				},
+			},
+			"expires_on": schema.StringAttribute{
+				MarkdownDescription: "Expiry of the token in RFC 3339 format in UTC, ex. for `terraform_data` triggers re-running before the token expires",
+				Computed:            true,
+			},
+			"expires_in_seconds": schema.Int64Attribute{
+				MarkdownDescription: "Seconds until the token expires, from the time it was opened",
+				Computed:            true,
+			},
+			"source_credential": schema.StringAttribute{
+				MarkdownDescription: "Type of the credential in the chain which issued the token, ex. `azure_pipelines_credential` or `azure_cli_credential`, for debugging the credential order.",
+				Computed:            true,
+			},
+			"resolved_tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant which issued the token, from the `tid` claim, ex. to discover the tenant of a service principal. It differs from the configured tenant when a multi-tenant application authenticates in another tenant. Null with a warning for opaque tokens, which aren't JWTs.",
+				Computed:            true,
+			},
+			"expires_on_raw": schema.StringAttribute{
+				MarkdownDescription: "Expiry of the token in RFC 3339 format, exactly as returned by the Azure SDK. The time zone isn't converted to UTC, so the offset depends on the credential (usually local time of the machine running Terraform). Useful when debugging time zone discrepancies with other tooling.",
+				Computed:            true,
+			},
+			"app_roles": schema.ListAttribute{
+				MarkdownDescription: "App roles granted to the application, from the `roles` claim of the token. Useful to check roles of the app registration before calling a protected API. Empty for tokens without roles, or tokens which aren't JWTs.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"decoded": schema.DynamicAttribute{
+				MarkdownDescription: "All claims of the token payload as an object, ex. `decoded.oid`, `decoded.tid`, `decoded.scp` or `decoded.roles`. Claims keep their JSON types. It's sensitive as a whole, as claims can contain personal data (ex. `upn` or `email`). Null for opaque tokens, which aren't JWTs.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"exec_credential": schema.BoolAttribute{
+				MarkdownDescription: "Populate `exec_credential_json` with the token in Kubernetes `client.authentication.k8s.io/v1` ExecCredential format, for kubeconfigs of AKS clusters. Scopes must be for AKS (`" + aksServerApplicationID + "/.default`).",
+				Optional:            true,
+			},
+			"exec_credential_json": schema.StringAttribute{
+				MarkdownDescription: "Token as Kubernetes ExecCredential JSON (`status.token` and `status.expirationTimestamp`), only populated when `exec_credential` is enabled.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"claims_json": schema.StringAttribute{
+				MarkdownDescription: "Payload of the token as a JSON string, exactly as in the token, ex. for `jsondecode` or passing to other tools. Sensitive, as claims can contain personal data. Null with a warning for opaque tokens, which aren't JWTs.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"dotenv": schema.StringAttribute{
+				MarkdownDescription: "Token formatted as a dotenv line (`<dotenv_variable>=<token>`), intended to be appended to CI env files like `$GITHUB_ENV`, so the token is available to subsequent steps.",
+				Computed:            true,
+				Sensitive:           true,
+			},
 		},
 	}
 }
@@ -70,47 +289,392 @@ func (d *TokenEphemeralResource) Configure(ctx context.Context, req ephemeral.Co
 		return
 	}
 
-	credential, ok := req.ProviderData.(*azidentity.ChainedTokenCredential)
+	providerData, ok := req.ProviderData.(*AzIdentityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Ephemeral Resource Configure Type",
-			fmt.Sprintf("Expected *azidentity.ChainedTokenCredential, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *AzIdentityProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.credential = credential
+	d.credential = providerData.Credential
+	d.credentialTypes = providerData.CredentialTypes
+	d.cloudName = providerData.CloudName
+	d.cloud = providerData.Cloud
+	d.allowedScopes = providerData.AllowedScopes
+	d.defaultScopes = providerData.DefaultScopes
+	d.scopeRequests = providerData.ScopeRequests
+	d.tokenCache = providerData.TokenCache
+	d.chainRetries = providerData.ChainRetries
+	d.chainRetryDelay = providerData.ChainRetryDelay
+	d.diagnosticsFile = providerData.DiagnosticsFile
 }
 
 func (r *TokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
 	var data TokenEphemeralResourceModel
 
+	defer func() {
+		if err := r.diagnosticsFile.write("azidentity_token", resp.Diagnostics); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to write diagnostics file: %s", err))
+		}
+	}()
+
 	// Read Terraform config data into the model
 	if resp.Diagnostics.Append(req.Config.Get(ctx, &data)...); resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Parse scopes
+	// Provider configuration isn't known yet, token can only be requested once it is
+	if r.credential == nil {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &ephemeral.Deferred{Reason: ephemeral.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		data.Token = types.StringUnknown()
+		data.Dotenv = types.StringUnknown()
+		data.TokenWithPrefix = types.StringUnknown()
+		data.AuthorizationHeader = types.StringUnknown()
+		data.ExpiresOn = types.StringUnknown()
+		data.ExpiresInSeconds = types.Int64Unknown()
+		data.ExpiresOnRaw = types.StringUnknown()
+		data.AppRoles = types.ListUnknown(types.StringType)
+		data.Decoded = types.DynamicUnknown()
+		data.ClaimsJSON = types.StringUnknown()
+		data.ExecCredentialJSON = types.StringUnknown()
+		data.SourceCredential = types.StringUnknown()
+		data.ResolvedTenantID = types.StringUnknown()
+		resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+		return
+	}
+
+	// Parse scopes, selecting scopes of the provider's cloud when they're given per cloud
 	scopes := make([]string, 0, len(data.Scopes.Elements()))
-	diags := data.Scopes.ElementsAs(ctx, &scopes, false)
-	if resp.Diagnostics.Append(diags...); diags.HasError() {
+	if !data.CloudScopes.IsNull() {
+		cloudScopes := map[string][]string{}
+		diags := data.CloudScopes.ElementsAs(ctx, &cloudScopes, false)
+		if resp.Diagnostics.Append(diags...); diags.HasError() {
+			return
+		}
+		selected, ok := cloudScopes[r.cloudName]
+		if !ok {
+			selected, ok = cloudScopes["default"]
+		}
+		if !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cloud_scopes"),
+				"No scopes for cloud",
+				fmt.Sprintf("cloud_scopes has neither '%s' (the provider's cloud) nor 'default' key.", r.cloudName),
+			)
+			return
+		}
+		scopes = selected
+	} else if !data.Scopes.IsNull() {
+		diags := data.Scopes.ElementsAs(ctx, &scopes, false)
+		if resp.Diagnostics.Append(diags...); diags.HasError() {
+			return
+		}
+	} else if len(r.defaultScopes) > 0 {
+		scopes = slices.Clone(r.defaultScopes)
+	} else {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("scopes"),
+			"Missing scopes",
+			"Set scopes or cloud_scopes, or default_scopes in the provider configuration.",
+		)
+		return
+	}
+	expanded, err := expandScopeAliases(scopes, r.cloudName, r.cloud)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("scopes"), "Unknown scope alias", err.Error())
+		return
+	}
+	scopes = expanded
+
+	if scope, ok := disallowedScope(scopes, r.allowedScopes); ok {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("scopes"),
+			"Scope not allowed",
+			fmt.Sprintf("Scope '%s' isn't allowed by the provider `allowed_scopes` (%s).", scope, strings.Join(r.allowedScopes, ", ")),
+		)
+		return
+	}
+
+	if scope, ok := nonAKSScope(scopes); ok && data.ExecCredential.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("exec_credential"),
+			"Scope not for AKS",
+			fmt.Sprintf("ExecCredential output is for AKS clusters, but scope '%s' isn't. Use scope `%s/.default`.", scope, aksServerApplicationID),
+		)
 		return
 	}
 
-	token, err := r.credential.GetToken(ctx, policy.TokenRequestOptions{
-		Claims:    data.Claims.ValueString(),
+	if r.scopeRequests != nil {
+		if exceeded := r.scopeRequests.add(scopes); len(exceeded) > 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("scopes"),
+				"Same scope requested by many tokens",
+				fmt.Sprintf("Scopes %s were requested by more than %d azidentity_token blocks in this run. Tokens of identical requests are reused, but consider sharing a single token (ex. from a module output) between resources needing the same scope. The threshold is configured by provider `repeated_scope_warning`.", strings.Join(exceeded, ", "), r.scopeRequests.threshold),
+			)
+		}
+	}
+
+	// Entra ID issues tokens for a single audience, so scopes for multiple resources are most likely a mistake
+	if audiences := scopeAudiences(scopes); len(audiences) > 1 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("scopes"),
+			"Scopes for multiple audiences",
+			fmt.Sprintf("Requested scopes belong to multiple audiences (%s), but a token can only be issued for one of them. Use a separate azidentity_token block for each audience.", strings.Join(audiences, ", ")),
+		)
+	}
+
+	// Merge claims, the explicit claims attribute first
+	claims := []string{}
+	if data.Claims.ValueString() != "" {
+		claims = append(claims, data.Claims.ValueString())
+	}
+	if !data.MergeClaims.IsNull() {
+		extraClaims := []string{}
+		diags := data.MergeClaims.ElementsAs(ctx, &extraClaims, false)
+		if resp.Diagnostics.Append(diags...); diags.HasError() {
+			return
+		}
+		claims = append(claims, extraClaims...)
+	}
+	claimsRequest := data.Claims.ValueString()
+	if !data.MergeClaims.IsNull() {
+		var err error
+		if claimsRequest, err = mergeClaims(claims); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("merge_claims"), "Invalid claims", err.Error())
+			return
+		}
+	}
+
+	tokenMode := data.TokenMode.ValueString()
+	if tokenMode != "" {
+		capable := []string{}
+		for _, credentialType := range r.credentialTypes {
+			if slices.Contains(credentialTokenModes[credentialType], tokenMode) {
+				capable = append(capable, credentialType)
+			}
+		}
+		if len(capable) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_mode"),
+				"No credential can issue requested token mode",
+				fmt.Sprintf("None of the configured credentials (%s) can issue %s tokens.", strings.Join(r.credentialTypes, ", "), tokenMode),
+			)
+			return
+		}
+	}
+
+	// Record attempts of each credential, so the failure can be attributed to them. The whole chain is retried when
+	// configured, the attempts of the last try are reported.
+	tokenCtx := ctx
+	var timeout time.Duration
+	if !data.Timeout.IsNull() {
+		// Validated by the schema
+		timeout, _ = time.ParseDuration(data.Timeout.ValueString())
+		var cancel context.CancelFunc
+		tokenCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	options := policy.TokenRequestOptions{
+		Claims:    claimsRequest,
 		Scopes:    scopes,
 		EnableCAE: data.EnableCAE.ValueBool(),
-	})
+		TenantID:  data.TenantID.ValueString(),
+	}
+	attempts := &credentialAttempts{}
+	var token azcore.AccessToken
+	var source string
+	// Tokens of identical requests earlier in the run are reused, the chain isn't attempted then
+	cached, ok := r.tokenCache.get(options)
+	if ok {
+		tflog.Debug(ctx, "Reusing token of an identical request")
+		token, source = cached.token, cached.source
+	}
+	for try := int64(0); !ok; try++ {
+		attempts = &credentialAttempts{}
+		token, err = r.credential.GetToken(withCredentialAttempts(tokenCtx, attempts), options)
+		if err == nil {
+			source = attempts.succeeded()
+			r.tokenCache.add(options, token, source)
+			break
+		}
+		// The chain wraps errors of its credentials, the last one tells why it stopped
+		cause := err
+		if last, ok := attempts.last(); ok && last.err != nil {
+			cause = last.err
+		}
+		if try >= r.chainRetries || !retryableTokenError(cause) {
+			break
+		}
+		tflog.Warn(ctx, fmt.Sprintf("Credential chain failed (try %d of %d), retrying in %s: %s", try+1, r.chainRetries+1, r.chainRetryDelay, err))
+		select {
+		case <-tokenCtx.Done():
+			err = tokenCtx.Err()
+		case <-time.After(r.chainRetryDelay):
+			continue
+		}
+		break
+	}
 
+	if err != nil && timeout > 0 && errors.Is(tokenCtx.Err(), context.DeadlineExceeded) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Token request timed out",
+			fmt.Sprintf("No token was issued within %s. Entra ID or the network is slow or unreachable, this isn't an authentication failure.\n\n%s", timeout, attempts.failureSummary(err)),
+		)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to get token", err.Error())
+		resp.Diagnostics.AddError("Unable to get token", attempts.failureSummary(err))
+		// Azure CLI fallback is common for local development, give actionable advice when it isn't logged in.
+		// Claims challenges (MFA) also suggest `az login`, but already include the exact command to run.
+		if last, ok := attempts.last(); ok && last.name == "azure_cli_credential" && last.err != nil && strings.Contains(last.err.Error(), "az login") && !strings.Contains(last.err.Error(), "--claims-challenge") {
+			resp.Diagnostics.AddError(
+				"Azure CLI is not logged in",
+				"The last credential in the chain, azure_cli_credential, has no signed in account. Run `az login` (optionally with `--tenant`), or configure a non-interactive credential when running in automation.",
+			)
+		}
+		if last, ok := attempts.last(); ok && last.name == "azure_developer_cli_credential" && last.err != nil && strings.Contains(last.err.Error(), "azd auth login") {
+			resp.Diagnostics.AddError(
+				"Azure Developer CLI is not logged in",
+				"The last credential in the chain, azure_developer_cli_credential, has no signed in account. Run `azd auth login` (optionally with `--tenant-id`), or configure a non-interactive credential when running in automation.",
+			)
+		}
 		return
 	}
 
+	if tokenMode != "" && source != "" && !slices.Contains(credentialTokenModes[source], tokenMode) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_mode"),
+			"Token issued in wrong mode",
+			fmt.Sprintf("Token was issued by %s, which can't issue %s tokens. Reorder the credentials, so a credential capable of %s tokens is tried first.", source, tokenMode, tokenMode),
+		)
+		return
+	}
+
+	if !data.RequestedLifetime.IsNull() {
+		// Validated by the schema
+		requested, _ := time.ParseDuration(data.RequestedLifetime.ValueString())
+		if lifetime := time.Until(token.ExpiresOn).Round(time.Second); !requestedLifetimeSupported && lifetime > requested {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("requested_lifetime"),
+				"Requested lifetime not supported",
+				fmt.Sprintf("Token is valid for %s, longer than requested %s. Token lifetimes can't be requested by clients, they're controlled by Entra ID token lifetime policies.", lifetime, requested),
+			)
+		}
+	}
+
 	data.Token = types.StringValue(token.Token)
+	data.SourceCredential = types.StringValue(source)
+	data.TokenWithPrefix = types.StringValue(token.Token)
+	if prefix := data.TokenPrefix.ValueString(); prefix != "" {
+		data.TokenWithPrefix = types.StringValue(prefix + " " + token.Token)
+	}
+	data.AuthorizationHeader = types.StringValue("Bearer " + token.Token)
+	reportedExpiry := token.ExpiresOn
+	if !data.ExpiryMargin.IsNull() {
+		// Validated by the schema
+		margin, _ := time.ParseDuration(data.ExpiryMargin.ValueString())
+		reportedExpiry = reportedExpiry.Add(-margin)
+	}
+	data.ExpiresOn = types.StringValue(reportedExpiry.UTC().Format(time.RFC3339))
+	data.ExpiresInSeconds = types.Int64Value(int64(time.Until(reportedExpiry).Seconds()))
+	data.ExpiresOnRaw = types.StringValue(token.ExpiresOn.Format(time.RFC3339))
+	appRoles := []string{}
+	data.Decoded = types.DynamicNull()
+	data.ClaimsJSON = types.StringNull()
+	data.ResolvedTenantID = types.StringNull()
+	// Tokens of some resources are opaque, they just don't expose claims
+	if claims, err := decodeTokenClaims(token.Token); err == nil {
+		appRoles = stringListClaim(claims, "roles")
+		data.Decoded = types.DynamicValue(claimValue(claims))
+		// Decoding succeeded above, so the payload is valid JSON
+		payload, _ := decodeTokenPayload(token.Token)
+		data.ClaimsJSON = types.StringValue(string(payload))
+		if tenantID, ok := claims["tid"].(string); ok && tenantID != "" {
+			data.ResolvedTenantID = types.StringValue(tenantID)
+		}
+	} else {
+		tflog.Debug(ctx, fmt.Sprintf("Token claims not available: %s", err))
+		resp.Diagnostics.AddAttributeWarning(path.Root("claims_json"), "Token claims not available", fmt.Sprintf("The token isn't a JWT, so its claims can't be decoded (%s) and claims_json and resolved_tenant_id are null. Tokens of some resources are opaque.", err))
+	}
+	var diags diag.Diagnostics
+	data.AppRoles, diags = types.ListValueFrom(ctx, types.StringType, appRoles)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	if !data.DotenvVariable.IsNull() {
+		data.Dotenv = types.StringValue(fmt.Sprintf("%s=%s", data.DotenvVariable.ValueString(), token.Token))
+	}
+
+	if data.ExecCredential.ValueBool() {
+		execCredential, err := formatExecCredential(token)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("exec_credential"), "Failed to format ExecCredential", err.Error())
+			return
+		}
+		data.ExecCredentialJSON = types.StringValue(execCredential)
+	}
+
+	if file := data.SummaryFile.ValueString(); file != "" {
+		fields := tokenSummaryFields
+		if !data.SummaryFields.IsNull() {
+			fields = []string{}
+			if resp.Diagnostics.Append(data.SummaryFields.ElementsAs(ctx, &fields, false)...); resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		if err := writeTokenSummary(file, fields, source, scopes, token); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("summary_file"), "Failed to write token summary", err.Error())
+			return
+		}
+		privateFile, err := json.Marshal(file)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to store token summary path", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, tokenSummaryPrivateKey, privateFile)...)
+	}
 
 	// Save data into ephemeral result data
 	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
 }
+
+// Close removes the token summary file, if one was written.
+func (r *TokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	privateFile, diags := req.Private.GetKey(ctx, tokenSummaryPrivateKey)
+	if resp.Diagnostics.Append(diags...); diags.HasError() || privateFile == nil {
+		return
+	}
+	var file string
+	if err := json.Unmarshal(privateFile, &file); err != nil {
+		resp.Diagnostics.AddError("Failed to read token summary path", err.Error())
+		return
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		resp.Diagnostics.AddWarning("Failed to remove token summary file", err.Error())
+	}
+}
+
+// Whether a failed token request may succeed when retried: timeouts, network errors, and throttling or server errors
+// of Entra ID. Other authentication failures, like an invalid secret, fail the same way again.
+func retryableTokenError(err error) bool {
+	retryableStatus := func(status int) bool {
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+	}
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) {
+		return authErr.RawResponse != nil && retryableStatus(authErr.RawResponse.StatusCode)
+	}
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return retryableStatus(respErr.StatusCode)
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+func TestRetryableTokenError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"timeout":                {err: fmt.Errorf("request: %w", context.DeadlineExceeded), want: true},
+		"network":                {err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: true},
+		"throttled":              {err: &azidentity.AuthenticationFailedError{RawResponse: &http.Response{StatusCode: http.StatusTooManyRequests}}, want: true},
+		"server error":           {err: &azidentity.AuthenticationFailedError{RawResponse: &http.Response{StatusCode: http.StatusServiceUnavailable}}, want: true},
+		"response server error":  {err: &azcore.ResponseError{StatusCode: http.StatusBadGateway}, want: true},
+		"invalid credentials":    {err: &azidentity.AuthenticationFailedError{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}},
+		"authentication failure": {err: &azidentity.AuthenticationFailedError{}},
+		"unavailable":            {err: azidentity.NewCredentialUnavailableError("not configured")},
+		"other":                  {err: errors.New("failed")},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := retryableTokenError(test.err); got != test.want {
+				t.Errorf("retryable = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
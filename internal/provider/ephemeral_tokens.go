@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	internalvalidator "github.com/rikpat/terraform-provider-azidentity/internal/validator"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &TokensEphemeralResource{}
+
+func NewTokensEphemeralResource() ephemeral.EphemeralResource {
+	return &TokensEphemeralResource{}
+}
+
+// TokensEphemeralResource requests a token for each entry of a scopes map.
+type TokensEphemeralResource struct {
+	credential      *azidentity.ChainedTokenCredential
+	cloudName       string
+	cloud           cloud.Configuration
+	allowedScopes   []string
+	diagnosticsFile *diagnosticsFile
+}
+
+// TokensEphemeralResourceModel describes the ephemeral resource data model.
+type TokensEphemeralResourceModel struct {
+	// Output
+	Tokens types.Map `tfsdk:"tokens"`
+	// Inputs
+	Scopes    types.Map  `tfsdk:"scopes"`
+	EnableCAE types.Bool `tfsdk:"enable_cae"`
+}
+
+// Token of a single entry of the tokens output.
+type tokensEntryModel struct {
+	Token     types.String `tfsdk:"token"`
+	ExpiresOn types.String `tfsdk:"expires_on"`
+	Error     types.String `tfsdk:"error"`
+}
+
+var tokensEntryType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"token":      types.StringType,
+	"expires_on": types.StringType,
+	"error":      types.StringType,
+}}
+
+func (r *TokensEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tokens"
+}
+
+func (r *TokensEphemeralResource) Schema(ctx context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches multiple access tokens at once, one for each entry of `scopes`, ex. tokens for Azure Resource Manager, Microsoft Graph and a database used in the same configuration. Use `azidentity_token` for options of a single token.",
+		Attributes: map[string]schema.Attribute{
+			"scopes": schema.MapAttribute{
+				MarkdownDescription: "Scopes of each token keyed by a name used in `tokens`, ex. `{ arm = [\"https://management.azure.com/.default\"], db = [\"postgres\"] }`. Aliases are replaced as in `azidentity_token` `scopes`.",
+				Required:            true,
+				ElementType:         types.SetType{ElemType: types.StringType},
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.ValueSetsAre(
+						setvalidator.SizeAtLeast(1),
+						setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1), internalvalidator.Scope()),
+					),
+				},
+			},
+			"enable_cae": schema.BoolAttribute{
+				Description: "Indicates whether to enable Continuous Access Evaluation (CAE) for the requested tokens. Requires a client supporting CAE. The default is false.",
+				Optional:    true,
+			},
+			"tokens": schema.MapNestedAttribute{
+				MarkdownDescription: "Tokens keyed by the names in `scopes`. When some tokens can't be requested, the others are still returned: failed entries have null `token` and `expires_on`, their `error` is set and a warning is reported. It's an error only when no token could be requested.",
+				Computed:            true,
+				Sensitive:           true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"token": schema.StringAttribute{
+							Description: "Output token for the scopes",
+							Computed:    true,
+							Sensitive:   true,
+						},
+						"expires_on": schema.StringAttribute{
+							Description: "Expiry of the token in RFC 3339 format in UTC",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Error of the token request when it failed, null for issued tokens",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TokensEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Always perform a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*AzIdentityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *AzIdentityProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.credential = providerData.Credential
+	d.cloudName = providerData.CloudName
+	d.cloud = providerData.Cloud
+	d.allowedScopes = providerData.AllowedScopes
+	d.diagnosticsFile = providerData.DiagnosticsFile
+}
+
+func (r *TokensEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data TokensEphemeralResourceModel
+
+	defer func() {
+		if err := r.diagnosticsFile.write("azidentity_tokens", resp.Diagnostics); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to write diagnostics file: %s", err))
+		}
+	}()
+
+	// Read Terraform config data into the model
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Provider configuration isn't known yet, tokens can only be requested once it is
+	if r.credential == nil {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &ephemeral.Deferred{Reason: ephemeral.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		data.Tokens = types.MapUnknown(tokensEntryType)
+		resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+		return
+	}
+
+	scopesByName := map[string][]string{}
+	if resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopesByName, false)...); resp.Diagnostics.HasError() {
+		return
+	}
+	names := make([]string, 0, len(scopesByName))
+	for name, scopes := range scopesByName {
+		names = append(names, name)
+		expanded, err := expandScopeAliases(scopes, r.cloudName, r.cloud)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("scopes").AtMapKey(name), "Unknown scope alias", err.Error())
+			continue
+		}
+		scopesByName[name] = expanded
+		if scope, ok := disallowedScope(scopesByName[name], r.allowedScopes); ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("scopes").AtMapKey(name),
+				"Scope not allowed",
+				fmt.Sprintf("Scope '%s' isn't allowed by the provider `allowed_scopes` (%s).", scope, strings.Join(r.allowedScopes, ", ")),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	slices.Sort(names)
+
+	// Request every token, so failures of all entries are reported at once
+	tokens := map[string]tokensEntryModel{}
+	failures := map[string]string{}
+	for _, name := range names {
+		attempts := &credentialAttempts{}
+		token, err := r.credential.GetToken(withCredentialAttempts(ctx, attempts), policy.TokenRequestOptions{
+			Scopes:    scopesByName[name],
+			EnableCAE: data.EnableCAE.ValueBool(),
+		})
+		if err != nil {
+			failures[name] = attempts.failureSummary(err)
+			tokens[name] = tokensEntryModel{
+				Token:     types.StringNull(),
+				ExpiresOn: types.StringNull(),
+				Error:     types.StringValue(err.Error()),
+			}
+			continue
+		}
+		tokens[name] = tokensEntryModel{
+			Token:     types.StringValue(token.Token),
+			ExpiresOn: types.StringValue(token.ExpiresOn.UTC().Format(time.RFC3339)),
+			Error:     types.StringNull(),
+		}
+	}
+	// Entries which succeeded are still usable, the result is only an error when there are none
+	for _, name := range names {
+		summary, failed := failures[name]
+		if !failed {
+			continue
+		}
+		if len(failures) == len(names) {
+			resp.Diagnostics.AddAttributeError(path.Root("scopes").AtMapKey(name), fmt.Sprintf("Unable to get token '%s'", name), summary)
+		} else {
+			resp.Diagnostics.AddAttributeWarning(path.Root("scopes").AtMapKey(name), fmt.Sprintf("Unable to get token '%s'", name), summary)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+	data.Tokens, diags = types.MapValueFrom(ctx, tokensEntryType, tokens)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into ephemeral result data
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const failingScope = "https://failing.example.com/.default"
+
+// Credential issuing tokens for any scope except failingScope.
+type scopeFailingCredential struct{}
+
+func (scopeFailingCredential) GetToken(_ context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if slices.Contains(opts.Scopes, failingScope) {
+		return azcore.AccessToken{}, errors.New("scope not granted")
+	}
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// Open azidentity_tokens with the scopes of each entry, requesting tokens from scopeFailingCredential.
+func openTokens(t *testing.T, scopesByName map[string]string) (*ephemeral.OpenResponse, TokensEphemeralResourceModel) {
+	t.Helper()
+	chain, err := azidentity.NewChainedTokenCredential([]azcore.TokenCredential{scopeFailingCredential{}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &TokensEphemeralResource{credential: chain, cloudName: "AzurePublic"}
+	schemaResp := &ephemeral.SchemaResponse{}
+	r.Schema(context.Background(), ephemeral.SchemaRequest{}, schemaResp)
+	setType := tftypes.Set{ElementType: tftypes.String}
+	entries := map[string]tftypes.Value{}
+	for name, scope := range scopesByName {
+		entries[name] = tftypes.NewValue(setType, []tftypes.Value{tftypes.NewValue(tftypes.String, scope)})
+	}
+	resp := &ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{
+			Schema: schemaResp.Schema,
+			Raw:    objectValue(t, schemaResp.Schema.Type(), nil),
+		},
+	}
+	r.Open(context.Background(), ephemeral.OpenRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw: objectValue(t, schemaResp.Schema.Type(), map[string]tftypes.Value{
+				"scopes": tftypes.NewValue(tftypes.Map{ElementType: setType}, entries),
+			}),
+		},
+	}, resp)
+	var data TokensEphemeralResourceModel
+	if !resp.Diagnostics.HasError() {
+		if diags := resp.Result.Get(context.Background(), &data); diags.HasError() {
+			t.Fatalf("Result: %v", diags)
+		}
+	}
+	return resp, data
+}
+
+func TestOpenTokensPartialFailure(t *testing.T) {
+	resp, data := openTokens(t, map[string]string{
+		"arm":    "https://management.azure.com/.default",
+		"failed": failingScope,
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Open: %v", resp.Diagnostics)
+	}
+	if warnings := resp.Diagnostics.Warnings(); len(warnings) != 1 || warnings[0].Summary() != "Unable to get token 'failed'" {
+		t.Errorf("warnings = %v, want one for the failed entry", warnings)
+	}
+	tokens := map[string]tokensEntryModel{}
+	if diags := data.Tokens.ElementsAs(context.Background(), &tokens, false); diags.HasError() {
+		t.Fatalf("tokens: %v", diags)
+	}
+	if arm := tokens["arm"]; arm.Token.ValueString() != "token" || arm.ExpiresOn.IsNull() || !arm.Error.IsNull() {
+		t.Errorf("arm = %+v, want an issued token", arm)
+	}
+	if failed := tokens["failed"]; !failed.Token.IsNull() || !failed.ExpiresOn.IsNull() || failed.Error.IsNull() {
+		t.Errorf("failed = %+v, want null token with an error", failed)
+	}
+}
+
+func TestOpenTokensAllFailed(t *testing.T) {
+	resp, _ := openTokens(t, map[string]string{"failed": failingScope})
+	if errs := resp.Diagnostics.Errors(); len(errs) != 1 || errs[0].Summary() != "Unable to get token 'failed'" {
+		t.Errorf("errors = %v, want one for the failed entry", errs)
+	}
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	internalvalidator "github.com/rikpat/terraform-provider-azidentity/internal/validator"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &ValidateEphemeralResource{}
+
+func NewValidateEphemeralResource() ephemeral.EphemeralResource {
+	return &ValidateEphemeralResource{}
+}
+
+// ValidateEphemeralResource checks that the credential chain can get a token, without exposing it.
+type ValidateEphemeralResource struct {
+	credential      *azidentity.ChainedTokenCredential
+	cloudName       string
+	cloud           cloud.Configuration
+	allowedScopes   []string
+	defaultScopes   []string
+	diagnosticsFile *diagnosticsFile
+}
+
+// ValidateEphemeralResourceModel describes the ephemeral resource data model.
+type ValidateEphemeralResourceModel struct {
+	// Output
+	Success          types.Bool   `tfsdk:"success"`
+	SourceCredential types.String `tfsdk:"source_credential"`
+	ErrorMessage     types.String `tfsdk:"error_message"`
+	// Inputs
+	Scope types.String `tfsdk:"scope"`
+}
+
+func (r *ValidateEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validate"
+}
+
+func (r *ValidateEphemeralResource) Schema(ctx context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks that the credential chain can get a token, ex. as a gate before a large apply in CI. The token is requested and discarded, only the outcome is exposed. A failed token request isn't an error, it's reported by `success` and `error_message`.",
+		Attributes: map[string]schema.Attribute{
+			"scope": schema.StringAttribute{
+				MarkdownDescription: "Scope of the token to request, ex. `https://management.azure.com/.default`. Aliases are replaced as in `azidentity_token` `scopes`. Defaults to the provider `default_scopes`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					internalvalidator.Scope(),
+				},
+			},
+			"success": schema.BoolAttribute{
+				MarkdownDescription: "Whether a credential of the chain got a token",
+				Computed:            true,
+			},
+			"source_credential": schema.StringAttribute{
+				MarkdownDescription: "Type of the credential which got the token, ex. `azure_cli_credential`. Null when no credential did.",
+				Computed:            true,
+			},
+			"error_message": schema.StringAttribute{
+				MarkdownDescription: "Errors of the credentials attempted in the chain, null on success",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ValidateEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Always perform a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*AzIdentityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *AzIdentityProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.credential = providerData.Credential
+	d.cloudName = providerData.CloudName
+	d.cloud = providerData.Cloud
+	d.allowedScopes = providerData.AllowedScopes
+	d.defaultScopes = providerData.DefaultScopes
+	d.diagnosticsFile = providerData.DiagnosticsFile
+}
+
+func (r *ValidateEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ValidateEphemeralResourceModel
+
+	defer func() {
+		if err := r.diagnosticsFile.write("azidentity_validate", resp.Diagnostics); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to write diagnostics file: %s", err))
+		}
+	}()
+
+	// Read Terraform config data into the model
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Provider configuration isn't known yet, the chain can only be validated once it is
+	if r.credential == nil {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &ephemeral.Deferred{Reason: ephemeral.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		data.Success = types.BoolUnknown()
+		data.SourceCredential = types.StringUnknown()
+		data.ErrorMessage = types.StringUnknown()
+		resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+		return
+	}
+
+	var scopes []string
+	if !data.Scope.IsNull() {
+		scopes = []string{data.Scope.ValueString()}
+	} else if len(r.defaultScopes) > 0 {
+		scopes = slices.Clone(r.defaultScopes)
+	} else {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("scope"),
+			"Missing scope",
+			"Set scope, or default_scopes in the provider configuration.",
+		)
+		return
+	}
+	expanded, err := expandScopeAliases(scopes, r.cloudName, r.cloud)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("scope"), "Unknown scope alias", err.Error())
+		return
+	}
+	scopes = expanded
+	if scope, ok := disallowedScope(scopes, r.allowedScopes); ok {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("scope"),
+			"Scope not allowed",
+			fmt.Sprintf("Scope '%s' isn't allowed by the provider `allowed_scopes` (%s).", scope, strings.Join(r.allowedScopes, ", ")),
+		)
+		return
+	}
+
+	attempts := &credentialAttempts{}
+	_, err = r.credential.GetToken(withCredentialAttempts(ctx, attempts), policy.TokenRequestOptions{Scopes: scopes})
+	data.Success = types.BoolValue(err == nil)
+	data.SourceCredential = types.StringNull()
+	data.ErrorMessage = types.StringNull()
+	if err != nil {
+		data.ErrorMessage = types.StringValue(attempts.failureSummary(err))
+	} else {
+		data.SourceCredential = types.StringValue(attempts.succeeded())
+	}
+
+	// Save data into ephemeral result data
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// Application ID of the AKS Microsoft Entra server, the audience of AKS cluster tokens in all clouds.
+const aksServerApplicationID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
+// Kubernetes client.authentication.k8s.io/v1 ExecCredential, as printed by exec plugins of kubeconfigs.
+type execCredential struct {
+	Kind       string               `json:"kind"`
+	APIVersion string               `json:"apiVersion"`
+	Spec       execCredentialSpec   `json:"spec"`
+	Status     execCredentialStatus `json:"status"`
+}
+
+type execCredentialSpec struct {
+	Interactive bool `json:"interactive"`
+}
+
+type execCredentialStatus struct {
+	ExpirationTimestamp string `json:"expirationTimestamp"`
+	Token               string `json:"token"`
+}
+
+// Get the first scope which isn't for AKS clusters, as ExecCredentials are only used by AKS.
+func nonAKSScope(scopes []string) (string, bool) {
+	for _, scope := range scopes {
+		if !strings.HasPrefix(scope, aksServerApplicationID+"/") && !strings.HasPrefix(scope, "api://"+aksServerApplicationID+"/") {
+			return scope, true
+		}
+	}
+	return "", false
+}
+
+// Format the token as ExecCredential JSON.
+func formatExecCredential(token azcore.AccessToken) (string, error) {
+	out, err := json.Marshal(execCredential{
+		Kind:       "ExecCredential",
+		APIVersion: "client.authentication.k8s.io/v1",
+		Status: execCredentialStatus{
+			ExpirationTimestamp: token.ExpiresOn.UTC().Format(time.RFC3339),
+			Token:               token.Token,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed formatting ExecCredential: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+const (
+	// Set by GitHub Actions for jobs with `id-token: write` permission.
+	githubOIDCRequestURLEnv   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	githubOIDCRequestTokenEnv = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+	// Audience expected by Entra ID federated identity credentials.
+	defaultOIDCAudience = "api://AzureADTokenExchange"
+)
+
+// Create a client assertion callback, which fetches an OIDC token from GitHub Actions every time it's called. The
+// token is requested with the client of token requests, so proxy and CA settings apply, or the default client when
+// client is nil.
+func newGitHubOIDCAssertion(audience string, client *http.Client) (func(context.Context) (string, error), error) {
+	requestURL, urlOk := os.LookupEnv(githubOIDCRequestURLEnv)
+	requestToken, tokenOk := os.LookupEnv(githubOIDCRequestTokenEnv)
+	if !urlOk || !tokenOk {
+		return nil, fmt.Errorf("%s and %s environment variables are not set. Make sure the workflow runs in GitHub Actions and the job has `permissions: id-token: write`", githubOIDCRequestURLEnv, githubOIDCRequestTokenEnv)
+	}
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", githubOIDCRequestURLEnv, err)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	return func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer "+requestToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed requesting GitHub OIDC token: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed reading GitHub OIDC token response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed requesting GitHub OIDC token: %s: %s", resp.Status, string(body))
+		}
+
+		var result struct {
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return "", fmt.Errorf("failed parsing GitHub OIDC token response: %w", err)
+		}
+		if result.Value == "" {
+			return "", fmt.Errorf("GitHub OIDC token response didn't contain a token")
+		}
+		return result.Value, nil
+	}, nil
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGitHubOIDCAssertionTrustsCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("audience"); got != defaultOIDCAudience {
+			t.Errorf("audience = %q, want %q", got, defaultOIDCAudience)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer request-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"value":"oidc-token"}`))
+	}))
+	defer server.Close()
+	t.Setenv(githubOIDCRequestURLEnv, server.URL)
+	t.Setenv(githubOIDCRequestTokenEnv, "request-token")
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	client, diags := transportClient(&AzIdentityProviderModel{
+		ProxyURL:   types.StringNull(),
+		CACertPath: types.StringNull(),
+		CACertPEM:  types.StringValue(string(caPEM)),
+	})
+	if diags.HasError() {
+		t.Fatalf("transportClient: %v", diags)
+	}
+
+	getAssertion, err := newGitHubOIDCAssertion(defaultOIDCAudience, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := getAssertion(context.Background())
+	if err != nil {
+		t.Fatalf("assertion with the CA certificate: %s", err)
+	}
+	if token != "oidc-token" {
+		t.Errorf("token = %q, want oidc-token", token)
+	}
+
+	getAssertion, err = newGitHubOIDCAssertion(defaultOIDCAudience, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getAssertion(context.Background()); err == nil {
+		t.Error("assertion without the CA certificate succeeded, want a TLS error")
+	}
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Shortest allowed health check interval, to keep background token requests bounded.
+const minHealthCheckInterval = time.Minute
+
+// Re-validates the credential chain periodically in the background, until stopped.
+type healthChecker struct {
+	mu        sync.Mutex
+	lastCheck time.Time
+	lastError string
+	stop      context.CancelFunc
+	done      chan struct{}
+}
+
+// Start checking the chain of the provider data every interval. The first check runs right away in the background,
+// so a broken chain is reported without waiting for an interval. Logs go to the logger of ctx, but ctx isn't used
+// for cancellation, as it ends with the Configure request.
+func startHealthChecker(ctx context.Context, providerData *AzIdentityProviderData, interval time.Duration) *healthChecker {
+	checkCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	h := &healthChecker{stop: stop, done: make(chan struct{})}
+	go func() {
+		defer close(h.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		h.check(checkCtx, providerData, interval)
+		for {
+			select {
+			case <-checkCtx.Done():
+				return
+			case <-ticker.C:
+				h.check(checkCtx, providerData, interval)
+			}
+		}
+	}()
+	tflog.Info(ctx, fmt.Sprintf("Started credential health check every %s", interval))
+	return h
+}
+
+// Run a single check, bounded by the interval so checks never overlap.
+func (h *healthChecker) check(ctx context.Context, providerData *AzIdentityProviderData, interval time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+	diags := verifyCredentialChain(ctx, providerData)
+	errors := make([]string, 0, diags.ErrorsCount())
+	for _, d := range diags.Errors() {
+		errors = append(errors, d.Detail())
+	}
+	if ctx.Err() == context.Canceled {
+		// Stopped during the check, the result isn't meaningful
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastCheck = time.Now()
+	h.lastError = strings.Join(errors, "\n")
+	if h.lastError != "" {
+		tflog.Warn(ctx, "Credential health check failed", map[string]interface{}{"error": h.lastError})
+	} else {
+		tflog.Debug(ctx, "Credential health check succeeded")
+	}
+}
+
+// Time and error of the last check. Time is zero before the first check, and error empty when it succeeded.
+func (h *healthChecker) lastResult() (time.Time, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastCheck, h.lastError
+}
+
+// Stop the background checks and wait for a running check to finish. Safe to call on nil.
+func (h *healthChecker) Stop() {
+	if h == nil {
+		return
+	}
+	h.stop()
+	<-h.done
+}
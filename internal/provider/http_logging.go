@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"context"
+
+	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Headers of token requests and responses logged with their values, in addition to the SDK defaults. Values of
+// other headers (including Authorization) are redacted by the SDK.
+var loggedHeaders = []string{clientRequestIDHeader, "client-request-id", "return-client-request-id", "x-ms-ests-server", "x-ms-clitelem"}
+
+// HTTP logging options of token requests. Bodies contain client secrets, assertions and tokens, so they're never logged.
+func httpLogOptions() policy.LogOptions {
+	return policy.LogOptions{
+		IncludeBody:    false,
+		AllowedHeaders: loggedHeaders,
+	}
+}
+
+// Route SDK logs of HTTP requests and authentication into tflog, or stop routing them when disabled. The SDK
+// listener is global, so the logger of the last configuration is used. Tokens which could still end up in
+// messages are redacted.
+func setHTTPLogging(ctx context.Context, enabled bool) {
+	if !enabled {
+		azlog.SetListener(nil)
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	azlog.SetEvents(azlog.EventRequest, azlog.EventResponse, azlog.EventResponseError, azlog.EventRetryPolicy, azidentity.EventAuthentication)
+	azlog.SetListener(func(event azlog.Event, msg string) {
+		tflog.Debug(ctx, jwtRegex.ReplaceAllString(msg, redacted), map[string]interface{}{"sdk_event": string(event)})
+	})
+}
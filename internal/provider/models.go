@@ -5,51 +5,231 @@ import (
 )
 
 type AzurePipelinesCredentialModel[T types.String | string] struct {
-	TenantID            T `tfsdk:"tenant_id" env:"ARM_TENANT_ID,AZURE_TENANT_ID"`
-	ClientID            T `tfsdk:"client_id" env:"ARM_CLIENT_ID,AZURE_CLIENT_ID" missing:"warn"`
-	ServiceConnectionID T `tfsdk:"service_connection_id" env:"ARM_OIDC_AZURE_SERVICE_CONNECTION_ID,AZURESUBSCRIPTION_SERVICE_CONNECTION_ID" missing:"warn"`
-	SystemAccessToken   T `tfsdk:"system_access_token" env:"ARM_OIDC_REQUEST_TOKEN,SYSTEM_ACCESSTOKEN" missing:"warn"`
+	TenantID              T `tfsdk:"tenant_id" env:"ARM_TENANT_ID,AZURE_TENANT_ID"`
+	ClientID              T `tfsdk:"client_id" env:"ARM_CLIENT_ID,AZURE_CLIENT_ID" missing:"warn"`
+	ServiceConnectionID   T `tfsdk:"service_connection_id" env:"ARM_OIDC_AZURE_SERVICE_CONNECTION_ID,AZURESUBSCRIPTION_SERVICE_CONNECTION_ID" missing:"warn"`
+	SystemAccessToken     T `tfsdk:"system_access_token" env:"ARM_OIDC_REQUEST_TOKEN,SYSTEM_ACCESSTOKEN" missing:"warn"`
+	SystemAccessTokenFile T `tfsdk:"system_access_token_file" replaces:"system_access_token"`
+	TaskVariablesFile     T `tfsdk:"task_variables_file"`
+	TenantIDEnv           T `tfsdk:"tenant_id_env" envfor:"tenant_id"`
+	ClientIDEnv           T `tfsdk:"client_id_env" envfor:"client_id"`
+	Cloud                 T `tfsdk:"cloud"`
+	AuthorityHost         T `tfsdk:"authority_host"`
 }
 type APcM = AzurePipelinesCredentialModel[types.String] //model
 type APcP = AzurePipelinesCredentialModel[string]       //parsed
 
 type ClientSecretCredentialModel[T types.String | string] struct {
-	TenantID     T `tfsdk:"tenant_id"`
-	ClientID     T `tfsdk:"client_id"`
-	ClientSecret T `tfsdk:"client_secret"`
+	TenantID      T `tfsdk:"tenant_id" env:"ARM_TENANT_ID,AZURE_TENANT_ID" missing:"error"`
+	ClientID      T `tfsdk:"client_id" env:"ARM_CLIENT_ID,AZURE_CLIENT_ID" missing:"error"`
+	ClientSecret  T `tfsdk:"client_secret" env:"ARM_CLIENT_SECRET,AZURE_CLIENT_SECRET" missing:"error"`
+	Cloud         T `tfsdk:"cloud"`
+	AuthorityHost T `tfsdk:"authority_host"`
 }
 type CScM = ClientSecretCredentialModel[types.String] //model
 type CScP = ClientSecretCredentialModel[string]       //parsed
 
+// Env variables follow the environment credential convention, so configuration can be moved between them. The
+// certificate password is also read from the azurerm provider variable, checked first like for client secrets.
 type ClientCertificateCredentialModel[T types.String | string] struct {
-	TenantID            T `tfsdk:"tenant_id"`
-	ClientID            T `tfsdk:"client_id"`
-	CertificatePath     T `tfsdk:"certificate_path"`
-	CertificatePassword T `tfsdk:"certificate_password"`
+	TenantID             T          `tfsdk:"tenant_id" env:"AZURE_TENANT_ID" missing:"error"`
+	ClientID             T          `tfsdk:"client_id" env:"AZURE_CLIENT_ID" missing:"error"`
+	CertificatePath      T          `tfsdk:"certificate_path" env:"AZURE_CLIENT_CERTIFICATE_PATH"`
+	CertificatePEM       T          `tfsdk:"certificate_pem"`
+	CertificateBase64    T          `tfsdk:"certificate_base64"`
+	CertificatePassword  T          `tfsdk:"certificate_password" env:"ARM_CLIENT_CERTIFICATE_PASSWORD,AZURE_CLIENT_CERTIFICATE_PASSWORD"`
+	SendCertificateChain types.Bool `tfsdk:"send_certificate_chain"`
+	TenantIDEnv          T          `tfsdk:"tenant_id_env" envfor:"tenant_id"`
+	ClientIDEnv          T          `tfsdk:"client_id_env" envfor:"client_id"`
+	Cloud                T          `tfsdk:"cloud"`
+	AuthorityHost        T          `tfsdk:"authority_host"`
 }
 type CCcM = ClientCertificateCredentialModel[types.String] //model
 type CCcP = ClientCertificateCredentialModel[string]       //parsed
 
+type AzureCLICredentialModel[T types.String | string] struct {
+	TenantID                   T          `tfsdk:"tenant_id"`
+	Subscription               T          `tfsdk:"subscription"`
+	AdditionallyAllowedTenants types.List `tfsdk:"additionally_allowed_tenants"`
+}
+type ACcM = AzureCLICredentialModel[types.String] //model
+type ACcP = AzureCLICredentialModel[string]       //parsed
+
+type AzureDeveloperCLICredentialModel[T types.String | string] struct {
+	TenantID                   T          `tfsdk:"tenant_id"`
+	AdditionallyAllowedTenants types.List `tfsdk:"additionally_allowed_tenants"`
+}
+type ADCcM = AzureDeveloperCLICredentialModel[types.String] //model
+type ADCcP = AzureDeveloperCLICredentialModel[string]       //parsed
+
+type DeviceCodeCredentialModel[T types.String | string] struct {
+	TenantID      T          `tfsdk:"tenant_id"`
+	ClientID      T          `tfsdk:"client_id"`
+	MessageWriter types.Bool `tfsdk:"message_writer"`
+	Cloud         T          `tfsdk:"cloud"`
+	AuthorityHost T          `tfsdk:"authority_host"`
+}
+type DCcM = DeviceCodeCredentialModel[types.String] //model
+type DCcP = DeviceCodeCredentialModel[string]       //parsed
+
+type InteractiveBrowserCredentialModel[T types.String | string] struct {
+	TenantID      T `tfsdk:"tenant_id"`
+	ClientID      T `tfsdk:"client_id"`
+	RedirectURL   T `tfsdk:"redirect_url"`
+	LoginHint     T `tfsdk:"login_hint"`
+	Cloud         T `tfsdk:"cloud"`
+	AuthorityHost T `tfsdk:"authority_host"`
+}
+type IBcM = InteractiveBrowserCredentialModel[types.String] //model
+type IBcP = InteractiveBrowserCredentialModel[string]       //parsed
+
 type ManagedIdentityCredentialModel[T types.String | string] struct {
-	ClientID T `tfsdk:"client_id"`
+	ClientID    T `tfsdk:"client_id"`
+	ResourceID  T `tfsdk:"resource_id"`
+	ObjectID    T `tfsdk:"object_id"`
+	ClientIDEnv T `tfsdk:"client_id_env" envfor:"client_id"`
 }
 type MIcM = ManagedIdentityCredentialModel[types.String] //model
 type MIcP = ManagedIdentityCredentialModel[string]       //parsed
 
 type WorkloadIdentityCredentialModel[T types.String | string] struct {
-	TenantID T `tfsdk:"tenant_id"`
-	ClientID T `tfsdk:"client_id"`
+	TenantID      T `tfsdk:"tenant_id"`
+	ClientID      T `tfsdk:"client_id"`
+	TokenFilePath T `tfsdk:"token_file_path"`
+	Token         T `tfsdk:"token"`
+	TenantIDEnv   T `tfsdk:"tenant_id_env" envfor:"tenant_id"`
+	ClientIDEnv   T `tfsdk:"client_id_env" envfor:"client_id"`
+	Cloud         T `tfsdk:"cloud"`
+	AuthorityHost T `tfsdk:"authority_host"`
 }
 type WIcM = WorkloadIdentityCredentialModel[types.String] //model
 type WIcP = WorkloadIdentityCredentialModel[string]       //parsed
 
+type ClientAssertionCredentialModel[T types.String | string] struct {
+	TenantID          T `tfsdk:"tenant_id" missing:"error"`
+	ClientID          T `tfsdk:"client_id" missing:"error"`
+	Assertion         T `tfsdk:"assertion"`
+	AssertionFilePath T `tfsdk:"assertion_file_path"`
+	Cloud             T `tfsdk:"cloud"`
+	AuthorityHost     T `tfsdk:"authority_host"`
+}
+type CAcM = ClientAssertionCredentialModel[types.String] //model
+type CAcP = ClientAssertionCredentialModel[string]       //parsed
+
+type OnBehalfOfCredentialModel[T types.String | string] struct {
+	TenantID            T `tfsdk:"tenant_id" missing:"error"`
+	ClientID            T `tfsdk:"client_id" missing:"error"`
+	UserAssertion       T `tfsdk:"user_assertion"`
+	ClientSecret        T `tfsdk:"client_secret"`
+	CertificatePath     T `tfsdk:"certificate_path"`
+	CertificatePassword T `tfsdk:"certificate_password"`
+	Assertion           T `tfsdk:"assertion"`
+	Cloud               T `tfsdk:"cloud"`
+	AuthorityHost       T `tfsdk:"authority_host"`
+}
+type OBOcM = OnBehalfOfCredentialModel[types.String] //model
+type OBOcP = OnBehalfOfCredentialModel[string]       //parsed
+
+type UsernamePasswordCredentialModel[T types.String | string] struct {
+	TenantID      T `tfsdk:"tenant_id" missing:"error"`
+	ClientID      T `tfsdk:"client_id" missing:"error"`
+	Username      T `tfsdk:"username" env:"AZURE_USERNAME" missing:"error"`
+	Password      T `tfsdk:"password" env:"AZURE_PASSWORD" missing:"error"`
+	Cloud         T `tfsdk:"cloud"`
+	AuthorityHost T `tfsdk:"authority_host"`
+}
+type UPcM = UsernamePasswordCredentialModel[types.String] //model
+type UPcP = UsernamePasswordCredentialModel[string]       //parsed
+
+type GitHubOIDCCredentialModel[T types.String | string] struct {
+	TenantID      T `tfsdk:"tenant_id" env:"ARM_TENANT_ID,AZURE_TENANT_ID"`
+	ClientID      T `tfsdk:"client_id" env:"ARM_CLIENT_ID,AZURE_CLIENT_ID" missing:"warn"`
+	Audience      T `tfsdk:"audience"`
+	TenantIDEnv   T `tfsdk:"tenant_id_env" envfor:"tenant_id"`
+	ClientIDEnv   T `tfsdk:"client_id_env" envfor:"client_id"`
+	Cloud         T `tfsdk:"cloud"`
+	AuthorityHost T `tfsdk:"authority_host"`
+}
+type GHOcM = GitHubOIDCCredentialModel[types.String] //model
+type GHOcP = GitHubOIDCCredentialModel[string]       //parsed
+
+// Options inherited by all credentials, from the provider common block.
+type CommonCredentialModel struct {
+	TenantID                        types.String `tfsdk:"tenant_id"`
+	ClientID                        types.String `tfsdk:"client_id"`
+	AdditionallyAllowedTenants      types.List   `tfsdk:"additionally_allowed_tenants"`
+	AdditionallyAllowedTenantsMerge types.String `tfsdk:"additionally_allowed_tenants_merge"`
+	DisableInstanceDiscovery        types.Bool   `tfsdk:"disable_instance_discovery"`
+}
+
+// Sources of DefaultAzureCredential are only toggled, so the model isn't parsed with env variables.
+type DefaultAzureCredentialModel struct {
+	TenantID                types.String `tfsdk:"tenant_id"`
+	ClientID                types.String `tfsdk:"client_id"`
+	ExcludeEnvironment      types.Bool   `tfsdk:"exclude_environment"`
+	ExcludeWorkloadIdentity types.Bool   `tfsdk:"exclude_workload_identity"`
+	ExcludeManagedIdentity  types.Bool   `tfsdk:"exclude_managed_identity"`
+	ExcludeCLI              types.Bool   `tfsdk:"exclude_cli"`
+	ExcludeDeveloperCLI     types.Bool   `tfsdk:"exclude_developer_cli"`
+	ExcludePowerShell       types.Bool   `tfsdk:"exclude_powershell"`
+	Cloud                   types.String `tfsdk:"cloud"`
+	AuthorityHost           types.String `tfsdk:"authority_host"`
+}
+
+// Cloud for disconnected environments like Azure Stack Hub, built into cloud.Configuration of the SDK.
+type CustomCloudModel struct {
+	ActiveDirectoryAuthorityHost types.String `tfsdk:"active_directory_authority_host"`
+	TokenAudience                types.String `tfsdk:"token_audience"`
+	Services                     types.Map    `tfsdk:"services"`
+}
+
+type CustomCloudServiceModel struct {
+	Audience types.String `tfsdk:"audience"`
+	Endpoint types.String `tfsdk:"endpoint"`
+}
+
 // AzIdentityProviderModel describes the provider data model.
 type AzIdentityProviderModel struct {
-	Cloud                       types.String `tfsdk:"cloud"`
-	Credentials                 types.List   `tfsdk:"credentials"`
-	AzurePipelinesCredential    types.Object `tfsdk:"azure_pipelines_credential"`
-	ClientSecretCredential      types.Object `tfsdk:"client_secret_credential"`
-	ClientCertificateCredential types.Object `tfsdk:"client_certificate_credential"`
-	ManagedIdentityCredential   types.Object `tfsdk:"managed_identity_credential"`
-	WorkloadIdentityCredential  types.Object `tfsdk:"workload_identity_credential"`
+	Cloud                        types.String `tfsdk:"cloud"`
+	CloudConfigurationJSON       types.String `tfsdk:"cloud_configuration_json"`
+	AuthorityHost                types.String `tfsdk:"authority_host"`
+	CustomCloud                  types.Object `tfsdk:"custom_cloud"`
+	Credentials                  types.List   `tfsdk:"credentials"`
+	CredentialsFromEnv           types.Bool   `tfsdk:"credentials_from_env"`
+	Common                       types.Object `tfsdk:"common"`
+	CredentialLogLevels          types.Map    `tfsdk:"credential_log_levels"`
+	LogCredentialChain           types.Bool   `tfsdk:"log_credential_chain"`
+	EnableHTTPLogging            types.Bool   `tfsdk:"enable_http_logging"`
+	OptimizeOrder                types.Bool   `tfsdk:"optimize_order"`
+	AllowHTTP                    types.Bool   `tfsdk:"allow_http"`
+	EagerAuth                    types.Bool   `tfsdk:"eager_auth"`
+	HealthCheckInterval          types.String `tfsdk:"health_check_interval"`
+	DiagnosticsFile              types.String `tfsdk:"diagnostics_file"`
+	EnablePersistentCache        types.Bool   `tfsdk:"enable_persistent_cache"`
+	ChainRetries                 types.Int64  `tfsdk:"chain_retries"`
+	ChainRetryDelay              types.String `tfsdk:"chain_retry_delay"`
+	CorrelationID                types.String `tfsdk:"correlation_id"`
+	AllowedScopes                types.List   `tfsdk:"allowed_scopes"`
+	DefaultScopes                types.Set    `tfsdk:"default_scopes"`
+	ProxyURL                     types.String `tfsdk:"proxy_url"`
+	RegionalAuthority            types.String `tfsdk:"regional_authority"`
+	CACertPath                   types.String `tfsdk:"ca_cert_path"`
+	CACertPEM                    types.String `tfsdk:"ca_cert_pem"`
+	RepeatedScopeWarning         types.Int64  `tfsdk:"repeated_scope_warning"`
+	AzurePipelinesCredential     types.Object `tfsdk:"azure_pipelines_credential"`
+	ClientSecretCredential       types.Object `tfsdk:"client_secret_credential"`
+	ClientCertificateCredential  types.Object `tfsdk:"client_certificate_credential"`
+	ClientSecretCredentials      types.List   `tfsdk:"client_secret_credentials"`
+	ClientCertificateCredentials types.List   `tfsdk:"client_certificate_credentials"`
+	ManagedIdentityCredential    types.Object `tfsdk:"managed_identity_credential"`
+	AzureCLICredential           types.Object `tfsdk:"azure_cli_credential"`
+	AzureDeveloperCLICredential  types.Object `tfsdk:"azure_developer_cli_credential"`
+	WorkloadIdentityCredential   types.Object `tfsdk:"workload_identity_credential"`
+	GitHubOIDCCredential         types.Object `tfsdk:"github_oidc_credential"`
+	ClientAssertionCredential    types.Object `tfsdk:"client_assertion_credential"`
+	UsernamePasswordCredential   types.Object `tfsdk:"username_password_credential"`
+	OnBehalfOfCredential         types.Object `tfsdk:"on_behalf_of_credential"`
+	DefaultAzureCredential       types.Object `tfsdk:"default_azure_credential"`
+	DeviceCodeCredential         types.Object `tfsdk:"device_code_credential"`
+	InteractiveBrowserCredential types.Object `tfsdk:"interactive_browser_credential"`
 }
@@ -0,0 +1,13 @@
+//go:build (darwin && cgo) || linux || windows
+
+package provider
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity/cache"
+)
+
+// Create a persistent token cache stored with the OS keyring (keychain on macOS, DPAPI on Windows).
+func newPersistentCache() (azidentity.Cache, error) {
+	return cache.New(&cache.Options{Name: persistentCacheName})
+}
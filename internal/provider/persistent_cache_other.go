@@ -0,0 +1,14 @@
+//go:build !(darwin && cgo) && !linux && !windows
+
+package provider
+
+import (
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// Persistent token cache isn't supported by the SDK on other platforms, and needs cgo for keychain access on macOS.
+func newPersistentCache() (azidentity.Cache, error) {
+	return azidentity.Cache{}, errors.New("persistent token cache isn't supported on this platform")
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// Azure Pipelines credential with the system access token read from a file, which the agent may refresh during
+// the run. The SDK credential takes the token as a string, so it's recreated whenever the file content changes.
+type pipelinesTokenFileCredential struct {
+	mu            sync.Mutex
+	file          string
+	token         string
+	credential    azcore.TokenCredential
+	newCredential func(systemAccessToken string) (azcore.TokenCredential, error)
+}
+
+// Read the system access token from the file.
+func readSystemAccessTokenFile(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed reading system access token file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("system access token file '%s' is empty", file)
+	}
+	return token, nil
+}
+
+// Create the credential with the current token of the file, failing when the file can't be read.
+func newPipelinesTokenFileCredential(file string, newCredential func(systemAccessToken string) (azcore.TokenCredential, error)) (*pipelinesTokenFileCredential, error) {
+	c := &pipelinesTokenFileCredential{file: file, newCredential: newCredential}
+	if _, err := c.current(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get the credential for the current token of the file, recreating it when the token changed.
+func (c *pipelinesTokenFileCredential) current() (azcore.TokenCredential, error) {
+	token, err := readSystemAccessTokenFile(c.file)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.credential == nil || token != c.token {
+		credential, err := c.newCredential(token)
+		if err != nil {
+			return nil, err
+		}
+		c.credential, c.token = credential, token
+	}
+	return c.credential, nil
+}
+
+func (c *pipelinesTokenFileCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	credential, err := c.current()
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+	return credential.GetToken(ctx, options)
+}
@@ -2,11 +2,24 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -14,11 +27,109 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/rikpat/terraform-provider-azidentity/internal/functions"
 	internalvalidator "github.com/rikpat/terraform-provider-azidentity/internal/validator"
 )
 
 var _ provider.Provider = &AzIdentityProvider{}
 var _ provider.ProviderWithEphemeralResources = &AzIdentityProvider{}
+var _ provider.ProviderWithFunctions = &AzIdentityProvider{}
+
+// Credential types supported in the credentials list.
+var credentialTypes = []string{
+	"environment_credential",
+	"azure_pipelines_credential",
+	"workload_identity_credential",
+	"managed_identity_credential",
+	"azure_cli_credential",
+	"client_secret_credential",
+	"client_certificate_credential",
+	"github_oidc_credential",
+	"default_azure_credential",
+	"device_code_credential",
+	"interactive_browser_credential",
+	"client_assertion_credential",
+	"username_password_credential",
+	"azure_developer_cli_credential",
+	"on_behalf_of_credential",
+}
+
+// Name of the persistent token cache, isolating it from caches of other applications.
+const persistentCacheName = "terraform-provider-azidentity"
+
+// Delay before retrying a failed credential chain, when not configured.
+const defaultChainRetryDelay = 5 * time.Second
+
+var guidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Credential types prompting the user to sign in, which should be tried after non-interactive ones.
+var interactiveCredentialTypes = []string{
+	"device_code_credential",
+	"interactive_browser_credential",
+}
+
+// Configuration blocks required by credential types, any of them is enough. Credential types not listed here can
+// be set up without configuration (from env variables or defaults).
+var credentialRequiredBlocks = map[string][]string{
+	"client_certificate_credential": {"client_certificate_credential", "client_certificate_credentials"},
+	"client_assertion_credential":   {"client_assertion_credential"},
+	"on_behalf_of_credential":       {"on_behalf_of_credential"},
+	"username_password_credential":  {"username_password_credential"},
+}
+
+// Validators of credentials list values, requiring configuration blocks of the listed credential types.
+func credentialBlockValidators() map[string]validator.String {
+	validators := make(map[string]validator.String, len(credentialRequiredBlocks))
+	for credentialType, blocks := range credentialRequiredBlocks {
+		expressions := make([]path.Expression, 0, len(blocks))
+		for _, block := range blocks {
+			expressions = append(expressions, path.MatchRoot(block))
+		}
+		validators[credentialType] = internalvalidator.AlsoRequiresAnyOf(expressions...)
+	}
+	return validators
+}
+
+// AzIdentityProviderData is passed from the provider to ephemeral resources and data sources.
+type AzIdentityProviderData struct {
+	Version string
+	// Credential is nil when provider configuration isn't known yet
+	Credential *azidentity.ChainedTokenCredential
+	// Types of credentials in the chain, in the order they're tried
+	CredentialTypes []string
+	// Diagnostics of token requests are appended to it, nil when disabled
+	DiagnosticsFile *diagnosticsFile
+	// Background credential health check, nil when disabled
+	HealthChecker *healthChecker
+	// Name of the selected cloud, Custom for clouds configured with JSON
+	CloudName string
+	// Configuration of the selected cloud
+	Cloud cloud.Configuration
+	// HTTP transport of token requests, nil for the default
+	Transport policy.Transporter
+	// Scopes tokens can be requested for, exact or prefix match. Empty allows all scopes.
+	AllowedScopes []string
+	// Scopes of tokens without scopes or cloud_scopes
+	DefaultScopes []string
+	// Counts token requests per scope during the run, nil when the warning is disabled
+	ScopeRequests *scopeRequestCounter
+	// Tokens of identical requests, reused during the run
+	TokenCache *tokenCache
+	// Outcome of setting up each configured credential, in the order of configuration
+	CredentialSetup []CredentialSetup
+	// Retries of token requests when the whole chain fails, and the delay between them
+	ChainRetries    int64
+	ChainRetryDelay time.Duration
+}
+
+// CredentialSetup is the outcome of setting up a single configured credential.
+type CredentialSetup struct {
+	Type  string
+	Label string
+	// Constructed is false when the credential failed to set up and is left out of the chain
+	Constructed bool
+	Error       string
+}
 
 // AzIdentityProvider defines the provider implementation.
 type AzIdentityProvider struct {
@@ -26,6 +137,8 @@ type AzIdentityProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+	// healthChecker of the last configuration, stopped when the provider is configured again
+	healthChecker *healthChecker
 }
 
 func (p *AzIdentityProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -41,13 +154,70 @@ Provider used for authenticating with resources supporting EntraID authenticatio
 
 Main usage is generating a token using Azure Pipelines Workload Federation Identity in IaC pipelines and falling back to azure_cli for local testing, but supports more credential types.
 
-Most credentials have options like selecting client_id and tenant_id, except for *environment* and *azure_cli* credentials which take all the options from external sources.
+Most credentials have options like selecting client_id and tenant_id, except for *environment* credential which takes all the options from external sources. *azure_cli* credential only allows selecting tenant_id and subscription.
 		`,
 		Attributes: map[string]schema.Attribute{
 			"cloud": schema.StringAttribute{
 				MarkdownDescription: "Cloud environment to target. Possible values are: ***AzurePublic*** (default), *AzureGovernment*, *AzureChina*",
 				Optional:            true,
 			},
+			"cloud_configuration_json": schema.StringAttribute{
+				MarkdownDescription: "Configuration of a custom cloud as JSON, for clouds not in `cloud` (ex. disconnected clouds). The structure follows `cloud.Configuration` of the Azure SDK: `{\"ActiveDirectoryAuthorityHost\": \"https://login.example/\", \"Services\": {\"resourceManager\": {\"Audience\": \"https://management.example\", \"Endpoint\": \"https://management.example\"}}}`. The authority host must be an https URL and each service needs an audience. Scope aliases (`sql`, `postgres`, `mysql`) use the audience of the service with the same name, ex. `{\"postgres\": {\"Audience\": \"https://ossrdbms-aad.database.example\"}}`, and it's an error to use an alias without one.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("cloud")),
+				},
+			},
+			"authority_host": schema.StringAttribute{
+				MarkdownDescription: "Microsoft Entra authority host overriding the one of `cloud`, ex. `https://login.contoso.local/adfs/` for Azure Stack Hub. Services of `cloud` are kept, so scope aliases still resolve for it. Use `cloud_configuration_json` when services differ too. Must be an https URL.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https://[^\s/?#]+(/\S*)?$`), "must be an https URL"),
+					stringvalidator.ConflictsWith(path.MatchRoot("cloud_configuration_json")),
+				},
+			},
+			"custom_cloud": schema.SingleNestedAttribute{
+				MarkdownDescription: "Custom cloud for disconnected environments the named clouds can't express, ex. Azure Stack Hub with its own authority, Azure Resource Manager audience and service endpoints. Same as `cloud_configuration_json` in attribute form. Scope aliases (`sql`, `postgres`, `mysql`) use the audience of the service with the same name in `services`, and it's an error to use an alias without one.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"active_directory_authority_host": schema.StringAttribute{
+						MarkdownDescription: "Microsoft Entra or AD FS authority host, ex. `https://adfs.local.azurestack.external/adfs/`. Must be an https URL.",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^https://[^\s/?#]+(/\S*)?$`), "must be an https URL"),
+						},
+					},
+					"token_audience": schema.StringAttribute{
+						MarkdownDescription: "Audience of Azure Resource Manager tokens, ex. `https://management.adfs.azurestack.local/<guid>`. Used for the `resourceManager` service unless it's in `services`.",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"services": schema.MapNestedAttribute{
+						MarkdownDescription: "Other services of the cloud keyed by the service name of the Azure SDK, ex. `resourceManager`, or by a scope alias (`sql`, `postgres`, `mysql`) to resolve it.",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"audience": schema.StringAttribute{
+									MarkdownDescription: "Audience of tokens for the service",
+									Required:            true,
+									Validators: []validator.String{
+										stringvalidator.LengthAtLeast(1),
+									},
+								},
+								"endpoint": schema.StringAttribute{
+									MarkdownDescription: "Endpoint of the service, ex. `https://management.local.azurestack.external`",
+									Optional:            true,
+								},
+							},
+						},
+					},
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(path.MatchRoot("cloud"), path.MatchRoot("cloud_configuration_json"), path.MatchRoot("authority_host")),
+				},
+			},
 			"credentials": schema.ListAttribute{
 				ElementType: types.StringType,
 
@@ -60,35 +230,194 @@ Most credentials have options like selecting client_id and tenant_id, except for
 	- managed_identity_credential
 	- azure_cli_credential
 	- client_secret_credential
-	- client_certificate_credential`,
+	- client_certificate_credential
+	- github_oidc_credential
+	- default_azure_credential
+	- device_code_credential
+	- interactive_browser_credential
+	- client_assertion_credential
+	- username_password_credential
+	- azure_developer_cli_credential
+	- on_behalf_of_credential`,
 				Required: true,
 				Validators: []validator.List{
 					listvalidator.UniqueValues(),
+					internalvalidator.InteractiveLast(interactiveCredentialTypes...),
 					listvalidator.ValueStringsAre(
-						stringvalidator.OneOf(
-							"environment_credential",
-							"azure_pipelines_credential",
-							"workload_identity_credential",
-							"managed_identity_credential",
-							"azure_cli_credential",
-							"client_secret_credential",
-							"client_certificate_credential",
-						),
-						internalvalidator.ValueBased(map[string]validator.String{
-							"client_secret_credential": stringvalidator.AlsoRequires(
-								path.MatchRoot("client_secret_credential"),
-							),
-							"client_certificate_credential": stringvalidator.AlsoRequires(
-								path.MatchRoot("client_certificate_credential"),
-							),
-						}),
+						stringvalidator.OneOf(credentialTypes...),
+						internalvalidator.ValueBased(credentialBlockValidators()),
 					),
 				},
 			},
+			"credentials_from_env": schema.BoolAttribute{
+				MarkdownDescription: "Override `credentials` with a comma separated list in *AZIDENTITY_CREDENTIALS* env variable when it's set, ex. `azure_pipelines_credential` in CI and `azure_cli_credential` locally, so the same configuration switches credentials by environment. Values are validated like `credentials`. Disabled by default.",
+				Optional:            true,
+			},
+			"common": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration inherited by all credentials. Values set in a credential block take precedence over the common ones, which take precedence over env variables. Managed identity and default Azure credentials don't inherit `client_id`, as it selects the managed identity instead of an application.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Tenant ID used by credentials which don't set one",
+					},
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Client ID used by credentials which don't set one",
+					},
+					"additionally_allowed_tenants": schema.ListAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Tenants credentials can get tokens from in addition to their tenant_id, `*` allows any tenant. Applies to all credentials supporting it, which is all except managed identity. Combined with the semicolon separated *AZURE_ADDITIONALLY_ALLOWED_TENANTS* env variable as set by `additionally_allowed_tenants_merge`.",
+						Validators: []validator.List{
+							listvalidator.UniqueValues(),
+							listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+					"additionally_allowed_tenants_merge": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "How `additionally_allowed_tenants` is combined with the *AZURE_ADDITIONALLY_ALLOWED_TENANTS* env variable. With `replace` the attribute is used instead of the env variable when it's set, so the configuration is explicit. With `union` tenants of both are used, so an env variable in CI extends the configured list. Duplicates are removed. Without the attribute, the env variable is used either way. Defaults to `replace`.",
+						Validators: []validator.String{
+							stringvalidator.OneOf("replace", "union"),
+						},
+					},
+					"disable_instance_discovery": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Disable the authority validation and instance discovery request, for disconnected clouds or private authority hosts",
+					},
+				},
+			},
+			"optimize_order": schema.BoolAttribute{
+				MarkdownDescription: `Reorder credentials, so the ones likely to get a token fastest are tried first. Credentials with the same estimate keep the order from ` + "`credentials`" + `. Disabled by default.
+
+	Estimate from fastest to slowest:
+	- Explicitly configured credentials (client secret, client certificate, client assertion) and credentials whose environment variables are present (*AZURE_CLIENT_SECRET*, *AZURE_CLIENT_CERTIFICATE_PATH* or *AZURE_USERNAME* for environment, *AZURE_FEDERATED_TOKEN_FILE* for workload identity, *SYSTEM_OIDCREQUESTURI* for azure pipelines, *ACTIONS_ID_TOKEN_REQUEST_URL* for GitHub OIDC)
+	- Environment based credentials without their environment variables
+	- azure_cli_credential, which runs a subprocess
+	- managed_identity_credential, which may wait for IMDS endpoint outside of Azure, and default_azure_credential, which may include it`,
+				Optional: true,
+			},
+			"correlation_id": schema.StringAttribute{
+				MarkdownDescription: "Correlation ID sent as *x-ms-client-request-id* header with all token requests, so they can be traced in Entra ID sign-in logs or support tickets. A random UUID is generated for each run when not set. The ID is logged at info level.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"chain_retries": schema.Int64Attribute{
+				MarkdownDescription: "Number of times a token request is retried when the whole credential chain fails with a transient error: a timeout, a network error, or a throttling (429) or server error (5xx) response, ex. when a network outage affects all credentials. Other errors, like invalid credentials, fail right away. This is on top of the HTTP retries of each credential. Defaults to 0 (no retries).",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 10),
+				},
+			},
+			"chain_retry_delay": schema.StringAttribute{
+				MarkdownDescription: "Delay before retrying a failed credential chain, as a duration (ex. `10s`). Defaults to `5s`.",
+				Optional:            true,
+				Validators: []validator.String{
+					internalvalidator.Duration(),
+				},
+			},
+			"eager_auth": schema.BoolAttribute{
+				MarkdownDescription: "Request a token for Azure Resource Manager of the selected cloud when the provider is configured, failing on error. Authentication problems are then reported immediately instead of when the first token is opened, at the cost of an extra token request (and the time it takes) on every run. Disabled by default.",
+				Optional:            true,
+			},
+			"health_check_interval": schema.StringAttribute{
+				MarkdownDescription: "Re-validate the credential chain in the background at this interval (ex. `15m`), by requesting a token for Azure Resource Manager like `eager_auth`. The time and error of the last check are exposed by the `azidentity_meta` data source. Checks only run while the provider process is alive, which is for the duration of a Terraform operation, so it's useful for long applies on warm agents. The first check runs right after the provider is configured. It's an error when the chain contains interactive credentials (`device_code_credential` or `interactive_browser_credential`), as background checks would prompt the user. Minimum is `1m`, disabled by default.",
+				Optional:            true,
+				Validators: []validator.String{
+					internalvalidator.Duration(),
+				},
+			},
+			"enable_persistent_cache": schema.BoolAttribute{
+				MarkdownDescription: "Cache tokens persistently, encrypted with the OS keyring (keychain on macOS, DPAPI on Windows, user keyring on Linux), so they're reused between plan and apply. Device code and interactive browser credentials sign in once and store the account details (authentication record, no tokens) in the user cache directory (ex. `~/.cache/terraform-provider-azidentity` on Linux), so later runs find the account in the cache instead of prompting again. Delete the file to sign in with another account. Supported by client secret, client certificate, client assertion, workload identity, Azure Pipelines, GitHub OIDC, device code and interactive browser credentials. When no keyring is available (ex. FreeBSD, or macOS builds without cgo like the released binaries), tokens are cached in memory with a warning. Disabled by default.",
+				Optional:            true,
+			},
+			"diagnostics_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a file all diagnostics (warnings and errors) of provider configuration and token requests are appended to, one JSON object per line with `source` (*configure* or *azidentity_token*), `severity`, `summary`, `detail` and `attribute` (path of the attribute, if any). Intended for CI tooling reacting to specific authentication problems. Sensitive attribute values, secrets from env variables and tokens are redacted. The file is readable only by the current user.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"allow_http": schema.BoolAttribute{
+				MarkdownDescription: "Allow sending credentials to HTTP endpoints, for testing against local authentication emulators. **Credentials are sent in clear text**, so never enable it with real credentials. The setting applies to all requests of the credentials, as the SDK can't restrict it to local hosts, and Entra ID authority hosts still need HTTPS. Disabled by default.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of an HTTP(S) proxy all token requests are sent through, including OIDC token requests of `github_oidc_credential`, ex. `http://proxy.example.com:3128`. Hosts matching the `NO_PROXY` environment variable are requested directly, add `169.254.169.254` for managed identities on Azure VMs. When not set, `HTTPS_PROXY` and `HTTP_PROXY` environment variables are used. Connection problems are reported when requesting tokens.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://[^/?#\s]+/?$`), "must be an http or https URL without path, ex. http://proxy.example.com:3128"),
+				},
+			},
+			"regional_authority": schema.StringAttribute{
+				MarkdownDescription: "Azure region of the regional token service used by application credentials, ex. `westus2`, or `autodetect` to detect the region of the Azure host. Regional endpoints have lower latency for high-throughput pipelines. Only credentials of applications support it (environment, azure_pipelines, workload_identity, client_secret, client_certificate, github_oidc, client_assertion and on_behalf_of credentials), other credentials get a warning and use the global endpoint. Defaults to `AZURE_REGIONAL_AUTHORITY_NAME` env variable. When set, the provider sets `AZURE_REGIONAL_AUTHORITY_NAME` for the lifetime of the provider process, as the Azure SDK only reads the region from it, so credentials recreated after configuration use the same region.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9]+$`), "must be an Azure region name, ex. westus2, or autodetect"),
+				},
+			},
+			"ca_cert_path": schema.StringAttribute{
+				MarkdownDescription: "Path of a PEM file with CA certificates trusted for token requests (including OIDC token requests of `github_oidc_credential`) in addition to the system certificates, ex. the private CA of a TLS inspecting proxy.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_pem")),
+				},
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates trusted for token requests in addition to the system certificates. Alternative to `ca_cert_path`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"allowed_scopes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes tokens can be requested for. A requested scope is allowed when it's equal to one of the entries, or is under an entry on a path boundary: the entry either ends with `/` or is followed by `/` in the scope (ex. `https://ossrdbms-aad.database.windows.net` allows any scope of that resource, but not `https://ossrdbms-aad.database.windows.net.example.com/.default`). Aliases in requested scopes are expanded before the check. All scopes are allowed when not set or empty.",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"default_scopes": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Scopes of `azidentity_token` blocks which set neither `scopes` nor `cloud_scopes`, ex. `https://management.azure.com/.default` when most tokens are for Azure Resource Manager. Aliases are replaced as in `scopes`.",
+				Optional:            true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"repeated_scope_warning": schema.Int64Attribute{
+				MarkdownDescription: "Warn once per scope when tokens for the same scope are requested by more than this many `azidentity_token` blocks in one run, suggesting to share the token. Tokens of identical requests are reused during the run, but each block still opens its own token. Defaults to 10, `0` disables the warning.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"credential_log_levels": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Log level of messages about each credential type, keyed by credential type. Possible values are *off*, *trace*, *debug*, *info* (default), *warn* and *error*. Useful to silence noisy credentials in large chains.",
+				Optional:            true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.OneOf(credentialTypes...)),
+					mapvalidator.ValueStringsAre(stringvalidator.OneOf("off", "trace", "debug", "info", "warn", "error")),
+				},
+			},
+			"log_credential_chain": schema.BoolAttribute{
+				MarkdownDescription: "Log a trace of setting up the credential chain at info level, with structured fields for each credential: position in the chain, whether its configuration block and detection env variables were found, and whether setup succeeded. Only names and booleans are logged, never configuration values. Disabled by default.",
+				Optional:            true,
+			},
+			"enable_http_logging": schema.BoolAttribute{
+				MarkdownDescription: "Log HTTP requests and responses of credentials (ex. to Entra ID and managed identity endpoints) at debug level, visible with `TF_LOG=DEBUG`. Bodies are never logged, and values of headers other than request IDs (including `Authorization`) are redacted. Disabled by default.",
+				Optional:            true,
+			},
 			"azure_pipelines_credential": schema.SingleNestedAttribute{
 				MarkdownDescription: "Configuration block for Azure Pipelines Credential. If using TerraformTask@5, no configuration needed unless you want to use different service connection than used for terraform. If using AzureCLI@2 or AzurePowershell@5, you need to also set SYSTEM_ACCESSTOKEN env variable, or provide access token as terraform variable.",
 				Optional:            true,
-				Attributes: map[string]schema.Attribute{
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
 					"tenant_id": schema.StringAttribute{
 						Optional:            true,
 						MarkdownDescription: "Optional tenant_id if it's different from used service connection (*ARM_TENANT_ID* or *AZURE_TENANT_ID*)",
@@ -105,13 +434,26 @@ Most credentials have options like selecting client_id and tenant_id, except for
 						Optional:            true,
 						Sensitive:           true,
 						MarkdownDescription: "Optional OIDC request token, if not using Terraform@5 task, or not setting *SYSTEM_ACCESSTOKEN* env variable",
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("system_access_token_file")),
+						},
 					},
-				},
+					"system_access_token_file": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional path of a file with the OIDC request token, for self-hosted agents writing it to a file. The file is read on every token request, so a token refreshed by the agent during the run is picked up. Takes precedence over env variables.",
+					},
+					"task_variables_file": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional path to a JSON file with task variables, used when a value isn't in config or env variables (ex. service connection ID not exported to the environment). Variables are looked up with the same names as env variables. Relative paths are resolved against *AGENT_TEMPDIRECTORY*.",
+					},
+					"tenant_id_env": envNameAttribute("tenant_id"),
+					"client_id_env": envNameAttribute("client_id"),
+				}),
 			},
 			"workload_identity_credential": schema.SingleNestedAttribute{
 				MarkdownDescription: "Configuration for workload identity credential. You can provide custom `client_id` and `tenant_id` if using multiple workload identities on single pod.",
 				Optional:            true,
-				Attributes: map[string]schema.Attribute{
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
 					"tenant_id": schema.StringAttribute{
 						Optional:            true,
 						MarkdownDescription: "Optional override of tenant_id, if not using the identity specified in service account annotations (in *AZURE_TENANT_ID* env variable)",
@@ -119,62 +461,421 @@ Most credentials have options like selecting client_id and tenant_id, except for
 					"client_id": schema.StringAttribute{
 						Optional:            true,
 						MarkdownDescription: "Optional override of client_id, if not using the identity specified in service account annotations (in *AZURE_CLIENT_ID* env variable)"},
-				},
+					"token_file_path": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional path to the service account token file, if not using the projected volume (in *AZURE_FEDERATED_TOKEN_FILE* env variable)",
+					},
+					"token": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Optional service account token value, for tokens retrieved through the Kubernetes API instead of a projected volume. Requires `tenant_id` and `client_id` (or *AZURE_TENANT_ID* and *AZURE_CLIENT_ID*). Conflicts with `token_file_path`.",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("token_file_path")),
+						},
+					},
+					"tenant_id_env": envNameAttribute("tenant_id"),
+					"client_id_env": envNameAttribute("client_id"),
+				}),
 			},
-			"managed_identity_credential": schema.SingleNestedAttribute{
-				MarkdownDescription: "Configuration for Managed Identity credential (optional `client_id` for user-assigned identity).",
+			"azure_cli_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for Azure CLI credential. The signed in account of `az login` is used. The block has no `cloud` or `authority_host`, as the CLI requests tokens from the cloud selected with `az cloud set`.",
 				Optional:            true,
 				Attributes: map[string]schema.Attribute{
-					"client_id": schema.StringAttribute{
+					"tenant_id": schema.StringAttribute{
 						Optional:            true,
-						MarkdownDescription: "Optional override of client_id, if using user-assigned identity",
+						MarkdownDescription: "Optional tenant to get the token from, ex. when the signed in user is a guest in it. Without it, the tenant of `az login` is used.",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(guidRegex, "must be a GUID"),
+						},
+					},
+					"subscription": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Name or ID of the subscription whose account is used, when several accounts are logged in. Without it, the current account of `az account show` is used.",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-zA-Z-_. ]+$`), "must be a subscription name or ID"),
+						},
+					},
+					"additionally_allowed_tenants": schema.ListAttribute{
+						ElementType:         types.StringType,
+						Optional:            true,
+						MarkdownDescription: "Tenants the credential may get tokens from in addition to `tenant_id`, overriding the ones in `common`. Use `*` to allow any tenant.",
+						Validators: []validator.List{
+							listvalidator.UniqueValues(),
+							listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
 					},
 				},
 			},
-			"client_secret_credential": schema.SingleNestedAttribute{
-				MarkdownDescription: "Configuration for a client secret credential. All properties are required, as there's already environment_credential that provides same functionality with env variables.",
+			"azure_developer_cli_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for Azure Developer CLI credential. The signed in account of `azd auth login` is used. The block has no `cloud` or `authority_host`, as azd requests tokens from the cloud selected with `azd config set cloud.name`.",
 				Optional:            true,
 				Attributes: map[string]schema.Attribute{
 					"tenant_id": schema.StringAttribute{
-						Required:            true,
-						MarkdownDescription: "Tenant ID of the service principal",
+						Optional:            true,
+						MarkdownDescription: "Optional tenant to get the token from. Without it, the tenant of the azd environment is used.",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(guidRegex, "must be a GUID"),
+						},
+					},
+					"additionally_allowed_tenants": schema.ListAttribute{
+						ElementType:         types.StringType,
+						Optional:            true,
+						MarkdownDescription: "Tenants the credential may get tokens from in addition to `tenant_id`, overriding the ones in `common`. Use `*` to allow any tenant.",
+						Validators: []validator.List{
+							listvalidator.UniqueValues(),
+							listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+				},
+			},
+			"device_code_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for device code credential, signing in a user interactively on another device. Useful on agents that can't open a browser. Place it after non-interactive credentials.",
+				Optional:            true,
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional tenant to sign in to. Without it, work and school accounts of any tenant can sign in (*organizations*).",
 					},
 					"client_id": schema.StringAttribute{
-						Required:            true,
-						MarkdownDescription: "Client ID of the service principal",
+						Optional:            true,
+						MarkdownDescription: "Optional client ID of the application users sign in to, the Azure CLI public client by default.",
 					},
-					"client_secret": schema.StringAttribute{
-						Required:            true,
+					"message_writer": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Write the device code prompt to the provider logs at info level (visible with `TF_LOG=INFO`). Without it the prompt is printed to the provider's stdout, which Terraform only writes to its logs.",
+					},
+				}),
+			},
+			"interactive_browser_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for interactive browser credential, signing in a user in the default browser. Useful for local development without Azure CLI. Place it after non-interactive credentials. The credential is skipped with a warning when no browser can be opened, ex. without a display (`DISPLAY` or `WAYLAND_DISPLAY`) or `xdg-open` on Linux. A browser failing to open at sign in fails the chain, as the SDK doesn't tell it apart from other authentication errors.",
+				Optional:            true,
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional tenant to sign in to. Without it, work and school accounts of any tenant can sign in (*organizations*).",
+					},
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional client ID of the application users sign in to, the Azure development application by default.",
+					},
+					"redirect_url": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Redirect URL of the application, matching a redirect URI of its registration. Only needed with `client_id`, when the application doesn't have `http://localhost` registered.",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+						},
+					},
+					"login_hint": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Username pre-populated in the sign in prompt, ex. `user@example.com`. Users can still sign in with another account.",
+					},
+				}),
+			},
+			"client_assertion_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for client assertion credential, authenticating an application with a federated identity credential using an assertion (ex. an OIDC token) from any issuer, for CI systems other than Azure Pipelines and GitHub Actions.",
+				Optional:            true,
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Tenant ID of the application, required unless set in `common`",
+					},
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Client ID of the application, required unless set in `common`",
+					},
+					"assertion": schema.StringAttribute{
+						Optional:            true,
 						Sensitive:           true,
-						MarkdownDescription: "Client Secret of the service principal",
+						MarkdownDescription: "Assertion used as is for all token requests. Prefer `assertion_file_path` for short-lived assertions.",
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("assertion_file_path")),
+						},
 					},
-				},
+					"assertion_file_path": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Path of a file with the assertion. It's read on every token request, so assertions rotated by the CI system are picked up.",
+					},
+				}),
 			},
-			"client_certificate_credential": schema.SingleNestedAttribute{
-				MarkdownDescription: "Configuration for a client certificate credential. All properties (except password in case of unencrypted certificate) are required, as there's already environment_credential that provides same functionality with env variables.",
+			"on_behalf_of_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for on-behalf-of credential, exchanging a token of a user sent to a middle-tier API (`user_assertion`) for a token of a downstream API, in the name of the same user. The API application authenticates with exactly one of `client_secret`, `certificate_path` and `assertion`.",
 				Optional:            true,
-				Attributes: map[string]schema.Attribute{
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
 					"tenant_id": schema.StringAttribute{
-						Required:            true,
-						MarkdownDescription: "Tenant ID of the service principal",
+						Optional:            true,
+						MarkdownDescription: "Tenant ID of the middle-tier application, required unless set in `common`",
 					},
 					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Client ID of the middle-tier application, required unless set in `common`",
+					},
+					"user_assertion": schema.StringAttribute{
 						Required:            true,
-						MarkdownDescription: "Client ID of the service principal",
+						Sensitive:           true,
+						MarkdownDescription: "Incoming access token of the user, issued for the middle-tier application",
+					},
+					"client_secret": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Client secret of the middle-tier application",
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("certificate_path"), path.MatchRelative().AtParent().AtName("assertion")),
+						},
 					},
 					"certificate_path": schema.StringAttribute{
-						Required:            true,
-						MarkdownDescription: "Path to certificate used for authentication. Can be relative to current working directory (terraform root).",
+						Optional:            true,
+						MarkdownDescription: "Path of a PEM or PKCS#12 certificate with the private key of the middle-tier application",
 					},
 					"certificate_password": schema.StringAttribute{
 						Optional:            true,
 						Sensitive:           true,
-						MarkdownDescription: "Password to certificate file, if used.",
+						MarkdownDescription: "Password of the certificate, if it's password protected",
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("certificate_path")),
+						},
+					},
+					"assertion": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Client assertion of the middle-tier application, ex. an OIDC token of a federated identity credential",
+					},
+				}),
+			},
+			"username_password_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for username password credential, signing in a user with a password, for legacy automation accounts. **It doesn't work with accounts requiring multifactor authentication**, token requests of such accounts fail. Entra ID enforces MFA for sign-ins to Azure, so prefer workload identities or service principals.",
+				Optional:            true,
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Tenant ID of the user, required unless set in `common`",
+					},
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Client ID of the application the user signs in to, required unless set in `common`",
+					},
+					"username": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Username, ex. `automation@example.com`. Defaults to `AZURE_USERNAME` env variable.",
+					},
+					"password": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Password of the user. Defaults to `AZURE_PASSWORD` env variable.",
 					},
+				}),
+			},
+			"managed_identity_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for Managed Identity credential (optional `client_id` for user-assigned identity). Tokens are requested from the managed identity endpoint directly, without the IMDS availability probe the SDK only does in its default credential chain, so there's no probe to skip on Azure hosts. Outside Azure the request waits for the endpoint to time out, consider placing it last or using `optimize_order`. On App Service, each deployment slot has its own identity: use a slot setting with `client_id_env` to select a slot-specific user-assigned identity. A warning is shown when the slot has no identity enabled. The block has no `cloud` or `authority_host`, as tokens are issued by the managed identity endpoint of the Azure host, not by an authority host.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional override of client_id, if using user-assigned identity",
+					},
+					"resource_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Resource ID of a user-assigned identity, ex. `/subscriptions/<subscription>/resourceGroups/<group>/providers/Microsoft.ManagedIdentity/userAssignedIdentities/<name>`, an alternative to `client_id`. System-assigned identity is used when none of `client_id`, `resource_id` and `object_id` is set.",
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("client_id"), path.MatchRelative().AtParent().AtName("client_id_env")),
+						},
+					},
+					"object_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Object (principal) ID of a user-assigned identity, an alternative to `client_id` and `resource_id`. Only one of `client_id` (or `client_id_env`), `resource_id` and `object_id` can be set, setting more is an error during plan rather than one of them taking precedence.",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(guidRegex, "must be a GUID"),
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("client_id"), path.MatchRelative().AtParent().AtName("client_id_env"), path.MatchRelative().AtParent().AtName("resource_id")),
+						},
+					},
+					"client_id_env": envNameAttribute("client_id"),
+				},
+			},
+			"client_secret_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for a client secret credential. Properties not set fall back to env variables, so the secret can be injected by CI instead of written into configuration. Unlike environment_credential, *ARM_* variables of the azurerm provider are supported too.",
+				Optional:            true,
+				Attributes:          clientSecretCredentialAttributes(),
+			},
+			"client_secret_credentials": schema.ListNestedAttribute{
+				MarkdownDescription: "Additional client secret credentials, tried in order after `client_secret_credential` when *client_secret_credential* is in `credentials`. Useful for rotating between multiple service principals.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: clientSecretCredentialAttributes(),
 				},
 			},
+			"client_certificate_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for a client certificate credential. Attributes not set are read from the env variables of environment_credential (*AZURE_TENANT_ID*, *AZURE_CLIENT_ID*, *AZURE_CLIENT_CERTIFICATE_PATH* and *AZURE_CLIENT_CERTIFICATE_PASSWORD*, or *ARM_CLIENT_CERTIFICATE_PASSWORD*), so configuration using environment_credential keeps working when switched to this block. The password is only needed for encrypted certificates.",
+				Optional:            true,
+				Attributes:          clientCertificateCredentialAttributes(),
+			},
+			"client_certificate_credentials": schema.ListNestedAttribute{
+				MarkdownDescription: "Additional client certificate credentials, tried in order after `client_certificate_credential` when *client_certificate_credential* is in `credentials`. Useful for rotating between multiple service principals.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: clientCertificateCredentialAttributes(),
+				},
+			},
+			"github_oidc_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for GitHub Actions OIDC federation. The OIDC token is requested from GitHub (*ACTIONS_ID_TOKEN_REQUEST_URL* and *ACTIONS_ID_TOKEN_REQUEST_TOKEN* env variables, available when the job has `id-token: write` permission) and used as a client assertion.",
+				Optional:            true,
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Tenant ID of the federated identity, if not set in *ARM_TENANT_ID* or *AZURE_TENANT_ID* env variable",
+					},
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Client ID of the federated identity, if not set in *ARM_CLIENT_ID* or *AZURE_CLIENT_ID* env variable",
+					},
+					"audience": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Audience of the requested OIDC token. Must match the audience of the federated identity credential. Defaults to `api://AzureADTokenExchange`",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"tenant_id_env": envNameAttribute("tenant_id"),
+					"client_id_env": envNameAttribute("client_id"),
+				}),
+			},
+			"default_azure_credential": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration for default Azure credential, which tries environment, workload identity, managed identity, Azure CLI, Azure Developer CLI and Azure PowerShell credentials in this order, taking all the options from external sources. Unlike in the SDK, each source can be excluded. Like in the SDK, the managed identity source first probes IMDS with a 1 second timeout and is skipped when it doesn't respond, so the chain doesn't wait for IMDS outside Azure. As it's a chain itself, combining it with other credentials in `credentials` is mostly redundant, and shows a warning.",
+				Optional:            true,
+				Attributes: withCloudAttributes(map[string]schema.Attribute{
+					"tenant_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional tenant_id for workload identity and developer tool credentials",
+					},
+					"client_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Optional client_id for workload identity credential and user-assigned managed identity (or *AZURE_CLIENT_ID* env variable)",
+					},
+					"exclude_environment": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Exclude environment credential",
+					},
+					"exclude_workload_identity": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Exclude workload identity credential",
+					},
+					"exclude_managed_identity": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Exclude managed identity credential, ex. to avoid waiting for IMDS endpoint outside of Azure",
+					},
+					"exclude_cli": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Exclude Azure CLI credential",
+					},
+					"exclude_developer_cli": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Exclude Azure Developer CLI credential",
+					},
+					"exclude_powershell": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Exclude Azure PowerShell credential",
+					},
+				}),
+			},
+		},
+	}
+}
+
+// Add attributes overriding the provider cloud to attributes of a credential block. Managed identity, Azure CLI and
+// Azure Developer CLI blocks don't have them, as their tokens don't come from the authority host of the provider.
+func withCloudAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["cloud"] = schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "Cloud of this credential, overriding the provider `cloud`, ex. for a service principal of another cloud. Possible values are the same as of the provider `cloud`. Scope aliases still resolve for the provider's cloud.",
+		Validators: []validator.String{
+			stringvalidator.OneOf(cloudNames...),
 		},
 	}
+	attributes["authority_host"] = schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "Microsoft Entra authority host of this credential, overriding the one of its `cloud` or the provider's authority host. Must be an https URL.",
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(regexp.MustCompile(`^https://[^\s/?#]+(/\S*)?$`), "must be an https URL"),
+		},
+	}
+	return attributes
+}
+
+// Attribute with custom name of the env variable of a field, for organizations with their own env conventions.
+func envNameAttribute(field string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: fmt.Sprintf("Name of a custom env variable with `%s`, checked before the default env variables", field),
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// Attributes of client secret credential, shared by single and list configuration.
+func clientSecretCredentialAttributes() map[string]schema.Attribute {
+	return withCloudAttributes(map[string]schema.Attribute{
+		"tenant_id": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Tenant ID of the service principal (or *ARM_TENANT_ID*, *AZURE_TENANT_ID* env variables)",
+		},
+		"client_id": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Client ID of the service principal (or *ARM_CLIENT_ID*, *AZURE_CLIENT_ID* env variables)",
+		},
+		"client_secret": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Client Secret of the service principal (or *ARM_CLIENT_SECRET*, *AZURE_CLIENT_SECRET* env variables)",
+		},
+	})
+}
+
+// Attributes of client certificate credential, shared by single and list configuration.
+func clientCertificateCredentialAttributes() map[string]schema.Attribute {
+	return withCloudAttributes(map[string]schema.Attribute{
+		"tenant_id": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Tenant ID of the service principal (or *AZURE_TENANT_ID* env variable)",
+		},
+		"client_id": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Client ID of the service principal (or *AZURE_CLIENT_ID* env variable)",
+		},
+		"certificate_path": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Path to certificate used for authentication (or *AZURE_CLIENT_CERTIFICATE_PATH* env variable). Can be relative to current working directory (terraform root). If the path is known during plan, the certificate is parsed during validation.",
+			Validators: []validator.String{
+				internalvalidator.ParsableCertificate(path.MatchRelative().AtParent().AtName("certificate_password"), "ARM_CLIENT_CERTIFICATE_PASSWORD", "AZURE_CLIENT_CERTIFICATE_PASSWORD"),
+			},
+		},
+		"certificate_pem": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "PEM content of the certificate and its private key, an alternative to `certificate_path` when the certificate can't be written to disk. Takes precedence over *AZURE_CLIENT_CERTIFICATE_PATH* env variable.",
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("certificate_path")),
+			},
+		},
+		"certificate_base64": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Base64 encoded certificate with its private key, ex. a PFX (PKCS#12) bundle from a secret store, an alternative to `certificate_path` and `certificate_pem`. Takes precedence over *AZURE_CLIENT_CERTIFICATE_PATH* env variable.",
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("certificate_path"), path.MatchRelative().AtParent().AtName("certificate_pem")),
+			},
+		},
+		"send_certificate_chain": schema.BoolAttribute{
+			Optional:            true,
+			MarkdownDescription: "Send the certificate chain with token requests, required for Subject Name and Issuer (SNI) authentication. Disabled by default.",
+		},
+		"certificate_password": schema.StringAttribute{
+			Optional:            true,
+			Sensitive:           true,
+			MarkdownDescription: "Password to certificate file, if used (or *ARM_CLIENT_CERTIFICATE_PASSWORD*, *AZURE_CLIENT_CERTIFICATE_PASSWORD* env variables).",
+		},
+		"tenant_id_env": envNameAttribute("tenant_id"),
+		"client_id_env": envNameAttribute("client_id"),
+	})
 }
 
 func (p *AzIdentityProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -187,13 +888,88 @@ func (p *AzIdentityProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	cred, diags := setupCredentialChain(ctx, &data)
+	var diagnosticsFile *diagnosticsFile
+	if !data.DiagnosticsFile.IsNull() && !data.DiagnosticsFile.IsUnknown() {
+		diagnosticsFile = newDiagnosticsFile(data.DiagnosticsFile.ValueString(), &data)
+		defer func() {
+			if err := diagnosticsFile.write("configure", resp.Diagnostics); err != nil {
+				resp.Diagnostics.AddAttributeWarning(path.Root("diagnostics_file"), "Failed to write diagnostics file", err.Error())
+			}
+		}()
+	}
+
+	// Configuration can be unknown during plan, when it's computed from other resources. Credentials are set up
+	// once it's known, instead of treating unknown values as missing.
+	if !req.Config.Raw.IsFullyKnown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		tflog.Info(ctx, "Provider configuration contains unknown values, deferring credential setup until they're known")
+		providerData := &AzIdentityProviderData{Version: p.version, DiagnosticsFile: diagnosticsFile}
+		resp.EphemeralResourceData = providerData
+		resp.DataSourceData = providerData
+		return
+	}
+
+	providerData, diags := setupCredentialChain(ctx, &data)
 
 	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.EphemeralResourceData = cred
+	providerData.Version = p.version
+	providerData.DiagnosticsFile = diagnosticsFile
+	providerData.ChainRetries = data.ChainRetries.ValueInt64()
+	providerData.ChainRetryDelay = defaultChainRetryDelay
+	if !data.ChainRetryDelay.IsNull() {
+		// Validated by the schema
+		providerData.ChainRetryDelay, _ = time.ParseDuration(data.ChainRetryDelay.ValueString())
+	}
+	if data.EagerAuth.ValueBool() {
+		if resp.Diagnostics.Append(verifyCredentialChain(ctx, providerData)...); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	p.healthChecker.Stop()
+	p.healthChecker = nil
+	if !data.HealthCheckInterval.IsNull() {
+		// Validated by the schema
+		interval, _ := time.ParseDuration(data.HealthCheckInterval.ValueString())
+		if interval < minHealthCheckInterval {
+			resp.Diagnostics.AddAttributeError(path.Root("health_check_interval"), "Invalid health check interval", fmt.Sprintf("Health check interval must be at least %s, got %s.", minHealthCheckInterval, interval))
+			return
+		}
+		// Unattended checks must not open a browser or wait for a device code in the middle of an apply
+		for _, credentialType := range providerData.CredentialTypes {
+			if slices.Contains(interactiveCredentialTypes, credentialType) {
+				resp.Diagnostics.AddAttributeError(path.Root("health_check_interval"), "Health check not supported with interactive credentials", fmt.Sprintf("The credential chain contains %s, which would prompt the user during background checks. Remove health_check_interval, or remove interactive credentials from the chain.", credentialType))
+				return
+			}
+		}
+		p.healthChecker = startHealthChecker(ctx, providerData, interval)
+		providerData.HealthChecker = p.healthChecker
+	}
+	if !data.DefaultScopes.IsNull() {
+		if resp.Diagnostics.Append(data.DefaultScopes.ElementsAs(ctx, &providerData.DefaultScopes, false)...); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if !data.AllowedScopes.IsNull() {
+		if resp.Diagnostics.Append(data.AllowedScopes.ElementsAs(ctx, &providerData.AllowedScopes, false)...); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	threshold := int64(defaultRepeatedScopeWarning)
+	if !data.RepeatedScopeWarning.IsNull() {
+		threshold = data.RepeatedScopeWarning.ValueInt64()
+	}
+	if threshold > 0 {
+		providerData.ScopeRequests = newScopeRequestCounter(threshold)
+	}
+	providerData.TokenCache = newTokenCache()
+	resp.EphemeralResourceData = providerData
+	resp.DataSourceData = providerData
 }
 
 func (p *AzIdentityProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -203,11 +979,35 @@ func (p *AzIdentityProvider) Resources(ctx context.Context) []func() resource.Re
 func (p *AzIdentityProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
 	return []func() ephemeral.EphemeralResource{
 		NewTokenEphemeralResource,
+		NewTokensEphemeralResource,
+		NewValidateEphemeralResource,
+	}
+}
+
+func (p *AzIdentityProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		functions.NewDecodeJWTFunction,
+		functions.NewPostgresConnectionStringFunction,
 	}
 }
 
 func (p *AzIdentityProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewMetaDataSource,
+		NewChainInfoDataSource,
+		NewCurrentDataSource,
+	}
+}
+
+// httpClient overrides the transport of all token requests when set with SetHTTPClient.
+var httpClient *http.Client
+
+// SetHTTPClient sets the HTTP client used for all token requests, including OIDC token requests of federated credentials.
+// Custom builds call it through the azidentityprovider package, as internal packages can't be imported.
+// It's intended for custom builds embedding the provider (custom transport, connection pooling, tracing), as an HTTP client
+// can't be passed through Terraform configuration. It must be called before the provider is served.
+func SetHTTPClient(client *http.Client) {
+	httpClient = client
 }
 
 func New(version string) func() provider.Provider {
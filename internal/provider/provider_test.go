@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestCredentialRequiredBlocks(t *testing.T) {
+	// Models of credential types, fields which must be configured and can't come from env need the block
+	models := map[string]interface{}{
+		"azure_pipelines_credential":     APcM{},
+		"workload_identity_credential":   WIcM{},
+		"managed_identity_credential":    MIcM{},
+		"azure_cli_credential":           ACcM{},
+		"client_secret_credential":       CScM{},
+		"client_certificate_credential":  CCcM{},
+		"github_oidc_credential":         GHOcM{},
+		"device_code_credential":         DCcM{},
+		"interactive_browser_credential": IBcM{},
+		"client_assertion_credential":    CAcM{},
+		"username_password_credential":   UPcM{},
+		"azure_developer_cli_credential": ADCcM{},
+		"on_behalf_of_credential":        OBOcM{},
+	}
+	for credentialType, model := range models {
+		modelType := reflect.TypeOf(model)
+		for i := 0; i < modelType.NumField(); i++ {
+			field := modelType.Field(i)
+			_, hasEnv := field.Tag.Lookup("env")
+			if field.Tag.Get("missing") != "error" || hasEnv {
+				continue
+			}
+			if _, ok := credentialRequiredBlocks[credentialType]; !ok {
+				t.Errorf("%s requires %s without env fallback, but isn't in credentialRequiredBlocks", credentialType, field.Tag.Get("tfsdk"))
+			}
+		}
+	}
+	resp := &provider.SchemaResponse{}
+	New("test")().Schema(context.Background(), provider.SchemaRequest{}, resp)
+	for credentialType, blocks := range credentialRequiredBlocks {
+		for _, block := range blocks {
+			if _, ok := resp.Schema.Attributes[block]; !ok {
+				t.Errorf("block %s of %s isn't a provider attribute", block, credentialType)
+			}
+		}
+	}
+}
+
+// Terraform value of a schema type, with the given attribute values and null for the others.
+func objectValue(t *testing.T, schemaType attr.Type, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+	objectType, ok := schemaType.TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("schema type %s isn't an object", schemaType)
+	}
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		if _, ok := attributes[name]; !ok {
+			t.Fatalf("unknown attribute %s", name)
+		}
+		attributes[name] = value
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// Configure the provider with an unknown attribute, ex. an output of a resource not created yet.
+func configureUnknown(t *testing.T, attribute string, deferralAllowed bool) *provider.ConfigureResponse {
+	t.Helper()
+	p := New("test")()
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw: objectValue(t, schemaResp.Schema.Type(), map[string]tftypes.Value{
+			attribute: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(context.Background(), provider.ConfigureRequest{
+		Config:             config,
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: deferralAllowed},
+	}, resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+func TestConfigureUnknownDeferred(t *testing.T) {
+	resp := configureUnknown(t, "proxy_url", true)
+	if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+		t.Errorf("Deferred = %v, want provider config unknown", resp.Deferred)
+	}
+	if resp.EphemeralResourceData != nil {
+		t.Errorf("provider data set for deferred configuration")
+	}
+}
+
+func TestConfigureUnknownCloud(t *testing.T) {
+	// No warnings about the unknown cloud, and no cloud is selected until it's known
+	resp := configureUnknown(t, "cloud", false)
+	providerData, ok := resp.EphemeralResourceData.(*AzIdentityProviderData)
+	if !ok {
+		t.Fatalf("EphemeralResourceData = %T, want *AzIdentityProviderData", resp.EphemeralResourceData)
+	}
+	if providerData.Credential != nil || providerData.CloudName != "" {
+		t.Errorf("credential set up for cloud %q before the cloud is known", providerData.CloudName)
+	}
+}
+
+func TestConfigureUnknownWithoutDeferral(t *testing.T) {
+	resp := configureUnknown(t, "proxy_url", false)
+	if resp.Deferred != nil {
+		t.Errorf("Deferred = %v, deferral isn't allowed", resp.Deferred)
+	}
+	providerData, ok := resp.EphemeralResourceData.(*AzIdentityProviderData)
+	if !ok {
+		t.Fatalf("EphemeralResourceData = %T, want *AzIdentityProviderData", resp.EphemeralResourceData)
+	}
+	if providerData.Credential != nil {
+		t.Errorf("credential set up from unknown configuration")
+	}
+}
+
+// Open azidentity_token with provider data of unknown provider configuration.
+func openUnknown(t *testing.T, deferralAllowed bool) *ephemeral.OpenResponse {
+	t.Helper()
+	r := NewTokenEphemeralResource()
+	configurable, ok := r.(ephemeral.EphemeralResourceWithConfigure)
+	if !ok {
+		t.Fatal("azidentity_token isn't configurable")
+	}
+	configureResp := &ephemeral.ConfigureResponse{}
+	configurable.Configure(context.Background(), ephemeral.ConfigureRequest{
+		ProviderData: configureUnknown(t, "proxy_url", false).EphemeralResourceData,
+	}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", configureResp.Diagnostics)
+	}
+
+	schemaResp := &ephemeral.SchemaResponse{}
+	r.Schema(context.Background(), ephemeral.SchemaRequest{}, schemaResp)
+	scopes := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "https://management.azure.com/.default"),
+	})
+	resp := &ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{
+			Schema: schemaResp.Schema,
+			Raw:    objectValue(t, schemaResp.Schema.Type(), nil),
+		},
+	}
+	r.Open(context.Background(), ephemeral.OpenRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    objectValue(t, schemaResp.Schema.Type(), map[string]tftypes.Value{"scopes": scopes}),
+		},
+		ClientCapabilities: ephemeral.OpenClientCapabilities{DeferralAllowed: deferralAllowed},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Open: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+func TestOpenTokenUnknownDeferred(t *testing.T) {
+	resp := openUnknown(t, true)
+	if resp.Deferred == nil || resp.Deferred.Reason != ephemeral.DeferredReasonProviderConfigUnknown {
+		t.Errorf("Deferred = %v, want provider config unknown", resp.Deferred)
+	}
+}
+
+func TestOpenTokenUnknownWithoutDeferral(t *testing.T) {
+	resp := openUnknown(t, false)
+	if resp.Deferred != nil {
+		t.Errorf("Deferred = %v, deferral isn't allowed", resp.Deferred)
+	}
+	var data TokenEphemeralResourceModel
+	if diags := resp.Result.Get(context.Background(), &data); diags.HasError() {
+		t.Fatalf("Result: %v", diags)
+	}
+	if !data.Token.IsUnknown() || !data.ExpiresOn.IsUnknown() {
+		t.Errorf("token = %s, expires_on = %s, want unknown", data.Token, data.ExpiresOn)
+	}
+}
+
+func TestCredentialCloudAttributes(t *testing.T) {
+	resp := &provider.SchemaResponse{}
+	New("test")().Schema(context.Background(), provider.SchemaRequest{}, resp)
+	// Tokens of these credentials don't come from the authority host, so overriding it is rejected by the schema
+	withoutCloud := []string{"managed_identity_credential", "azure_cli_credential", "azure_developer_cli_credential"}
+	for _, block := range withoutCloud {
+		objectType, ok := resp.Schema.Attributes[block].GetType().(types.ObjectType)
+		if !ok {
+			t.Fatalf("%s isn't an object attribute", block)
+		}
+		for _, attribute := range []string{"cloud", "authority_host"} {
+			if _, ok := objectType.AttrTypes[attribute]; ok {
+				t.Errorf("%s has %s, which it can't use", block, attribute)
+			}
+		}
+	}
+	for _, block := range []string{"client_secret_credential", "client_certificate_credential", "workload_identity_credential", "azure_pipelines_credential"} {
+		objectType, ok := resp.Schema.Attributes[block].GetType().(types.ObjectType)
+		if !ok {
+			t.Fatalf("%s isn't an object attribute", block)
+		}
+		for _, attribute := range []string{"cloud", "authority_host"} {
+			if _, ok := objectType.AttrTypes[attribute]; !ok {
+				t.Errorf("%s has no %s", block, attribute)
+			}
+		}
+	}
+}
+
+func TestHealthCheckRefusesInteractiveCredentials(t *testing.T) {
+	p := New("test")()
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+	credentials := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "device_code_credential"),
+	})
+	resp := &provider.ConfigureResponse{}
+	p.Configure(context.Background(), provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw: objectValue(t, schemaResp.Schema.Type(), map[string]tftypes.Value{
+				"credentials":           credentials,
+				"health_check_interval": tftypes.NewValue(tftypes.String, "1m"),
+			}),
+		},
+	}, resp)
+	var found bool
+	for _, d := range resp.Diagnostics.Errors() {
+		found = found || d.Summary() == "Health check not supported with interactive credentials"
+	}
+	if !found {
+		t.Errorf("diagnostics = %v, want an error about interactive credentials", resp.Diagnostics)
+	}
+}
+
+func TestInteractiveBrowserSkippedWithoutBrowser(t *testing.T) {
+	opener := browserAvailable
+	browserAvailable = func() error { return errors.New("no display") }
+	t.Cleanup(func() { browserAvailable = opener })
+
+	p := New("test")()
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+	credentials := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "interactive_browser_credential"),
+		tftypes.NewValue(tftypes.String, "azure_cli_credential"),
+	})
+	resp := &provider.ConfigureResponse{}
+	p.Configure(context.Background(), provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    objectValue(t, schemaResp.Schema.Type(), map[string]tftypes.Value{"credentials": credentials}),
+		},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("configure failed: %v", resp.Diagnostics)
+	}
+	var warned bool
+	for _, d := range resp.Diagnostics.Warnings() {
+		warned = warned || (d.Summary() == "Error setting up credential 'interactive_browser_credential'." && strings.Contains(d.Detail(), "no display"))
+	}
+	if !warned {
+		t.Errorf("diagnostics = %v, want a warning about the browser", resp.Diagnostics)
+	}
+	data, ok := resp.EphemeralResourceData.(*AzIdentityProviderData)
+	if !ok {
+		t.Fatalf("provider data = %T", resp.EphemeralResourceData)
+	}
+	if !slices.Equal(data.CredentialTypes, []string{"azure_cli_credential"}) {
+		t.Errorf("chain = %v, want only azure_cli_credential", data.CredentialTypes)
+	}
+}
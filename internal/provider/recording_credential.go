@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+var _ azcore.TokenCredential = &recordingCredential{}
+
+// recordingCredential wraps a credential in the chain, so results of token requests can be attributed to the credential type.
+type recordingCredential struct {
+	name string
+	// label identifies the configuration of the credential, when there are multiple credentials of the same type
+	label string
+	// index of the credential type in the credentials list
+	index      int
+	credential azcore.TokenCredential
+}
+
+// GetToken requests a token from the wrapped credential and records the outcome in the attempts stored in the context, if any.
+func (c *recordingCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	token, err := c.credential.GetToken(credentialLogContext(ctx, c.name, c.index), opts)
+	if attempts, ok := ctx.Value(credentialAttemptsKey{}).(*credentialAttempts); ok {
+		attempts.add(c.name, c.label, err)
+	}
+	return token, err
+}
+
+type credentialAttemptsKey struct{}
+
+type credentialAttempt struct {
+	name  string
+	label string
+	err   error
+}
+
+// credentialAttempts collects the results of a single token request. It's scoped to a context, so parallel requests don't mix.
+type credentialAttempts struct {
+	mu       sync.Mutex
+	attempts []credentialAttempt
+}
+
+func withCredentialAttempts(ctx context.Context, attempts *credentialAttempts) context.Context {
+	return context.WithValue(ctx, credentialAttemptsKey{}, attempts)
+}
+
+func (a *credentialAttempts) add(name string, label string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.attempts = append(a.attempts, credentialAttempt{name: name, label: label, err: err})
+}
+
+// Get the last attempted credential, false if there were no attempts.
+func (a *credentialAttempts) last() (credentialAttempt, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.attempts) == 0 {
+		return credentialAttempt{}, false
+	}
+	return a.attempts[len(a.attempts)-1], true
+}
+
+// Get the type of credential which returned a token, empty if none did.
+func (a *credentialAttempts) succeeded() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, attempt := range a.attempts {
+		if attempt.err == nil {
+			return attempt.name
+		}
+	}
+	return ""
+}
+
+// Format failed attempts as one line per credential. Falls back to the chain error if no attempt was recorded.
+func (a *credentialAttempts) failureSummary(chainErr error) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.attempts) == 0 {
+		return chainErr.Error()
+	}
+	var sb strings.Builder
+	sb.WriteString("Credentials attempted in the chain:\n")
+	for _, attempt := range a.attempts {
+		reason := "no error reported"
+		if attempt.err != nil {
+			reason = strings.ReplaceAll(strings.TrimSpace(attempt.err.Error()), "\n", "\n    ")
+		}
+		sb.WriteString(fmt.Sprintf("  - %s: %s\n", attempt.label, reason))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
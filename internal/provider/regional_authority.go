@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// Env variable the SDK reads the region of confidential client credentials from, when they're constructed. The SDK
+// doesn't have an option for it.
+const regionalAuthorityEnv = "AZURE_REGIONAL_AUTHORITY_NAME"
+
+// Region value letting MSAL detect the region of the Azure host.
+const regionalAuthorityAutodetect = "TryAutoDetect"
+
+// Credential types authenticating an application with a confidential client, which can use regional token service.
+var regionalCredentialTypes = []string{
+	"environment_credential",
+	"azure_pipelines_credential",
+	"workload_identity_credential",
+	"client_secret_credential",
+	"client_certificate_credential",
+	"github_oidc_credential",
+	"client_assertion_credential",
+	"on_behalf_of_credential",
+}
+
+// Set the regional authority for credentials constructed by the provider. The env variable is kept for the lifetime
+// of the provider process instead of being restored after Configure, as credentials may be recreated later (ex.
+// Azure Pipelines credentials reading the token from a file), and changing it back would race with token requests
+// of the health check. Terraform runs a provider process per provider configuration, so it doesn't leak into other
+// configurations. Credential types which can't use it get a warning.
+func setRegionalAuthority(region string, credentialTypes []string) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if region == "" {
+		return diags
+	}
+	for _, credentialType := range credentialTypes {
+		if !slices.Contains(regionalCredentialTypes, credentialType) {
+			diags.AddAttributeWarning(path.Root("regional_authority"), "Regional authority ignored",
+				fmt.Sprintf("%s doesn't support regional token service, it uses the global endpoint.", credentialType))
+		}
+	}
+	if region == "autodetect" {
+		region = regionalAuthorityAutodetect
+	}
+	if err := os.Setenv(regionalAuthorityEnv, region); err != nil {
+		diags.AddAttributeWarning(path.Root("regional_authority"), "Regional authority ignored", err.Error())
+	}
+	return diags
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// Number of token requests for the same scope in one run, before suggesting to share the token.
+const defaultRepeatedScopeWarning = 10
+
+// scopeRequestCounter counts token requests per scope over the lifetime of the configured provider.
+type scopeRequestCounter struct {
+	mu        sync.Mutex
+	threshold int64
+	counts    map[string]int64
+}
+
+func newScopeRequestCounter(threshold int64) *scopeRequestCounter {
+	return &scopeRequestCounter{threshold: threshold, counts: map[string]int64{}}
+}
+
+// Record a token request and return the scopes which just exceeded the threshold, so each is only reported once.
+func (c *scopeRequestCounter) add(scopes []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exceeded := []string{}
+	for _, scope := range scopes {
+		c.counts[scope]++
+		if c.counts[scope] == c.threshold+1 {
+			exceeded = append(exceeded, scope)
+		}
+	}
+	return exceeded
+}
+
+// Aliases of well-known scopes, by cloud name. Custom clouds resolve them from services of the same name.
+var scopeAliases = map[string]map[string]string{
+	"sql": {
+		"AzurePublic":     "https://database.windows.net/.default",
+		"AzureGovernment": "https://database.usgovcloudapi.net/.default",
+		"AzureChina":      "https://database.chinacloudapi.cn/.default",
+	},
+	"postgres": {
+		"AzurePublic":     "https://ossrdbms-aad.database.windows.net/.default",
+		"AzureGovernment": "https://ossrdbms-aad.database.usgovcloudapi.net/.default",
+		"AzureChina":      "https://ossrdbms-aad.database.chinacloudapi.cn/.default",
+	},
+	"mysql": {
+		"AzurePublic":     "https://ossrdbms-aad.database.windows.net/.default",
+		"AzureGovernment": "https://ossrdbms-aad.database.usgovcloudapi.net/.default",
+		"AzureChina":      "https://ossrdbms-aad.database.chinacloudapi.cn/.default",
+	},
+}
+
+// Replace scope aliases with scopes of the cloud. Clouds without scopes of the alias, like custom clouds, use the
+// audience of the service named like the alias in their configuration, and it's an error when there's none.
+func expandScopeAliases(scopes []string, cloudName string, config cloud.Configuration) ([]string, error) {
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		alias, ok := scopeAliases[scope]
+		if !ok {
+			out = append(out, scope)
+			continue
+		}
+		if expanded, ok := alias[cloudName]; ok {
+			out = append(out, expanded)
+			continue
+		}
+		service, ok := config.Services[cloud.ServiceName(scope)]
+		if !ok || service.Audience == "" {
+			return nil, fmt.Errorf("scope alias '%s' has no scope in cloud %s, add a '%s' service with its audience to the cloud configuration", scope, cloudName, scope)
+		}
+		out = append(out, strings.TrimSuffix(service.Audience, "/")+"/.default")
+	}
+	return out, nil
+}
+
+// Get the first scope not matching any of the allowed scopes. All scopes are allowed when the allowlist is empty.
+func disallowedScope(scopes []string, allowed []string) (string, bool) {
+	if len(allowed) == 0 {
+		return "", false
+	}
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(allowed, func(a string) bool { return scopeAllowed(scope, a) }) {
+			return scope, true
+		}
+	}
+	return "", false
+}
+
+// Check a scope against an allowed scope. It matches exactly, or by prefix on a path boundary, so
+// `https://vault.azure.net` allows `https://vault.azure.net/.default` but not `https://vault.azure.net.evil.com/.default`.
+func scopeAllowed(scope string, allowed string) bool {
+	if scope == allowed {
+		return true
+	}
+	if !strings.HasPrefix(scope, allowed) {
+		return false
+	}
+	return strings.HasSuffix(allowed, "/") || scope[len(allowed)] == '/'
+}
+
+// Get the audience (resource) a scope belongs to. Scopes without a resource (ex. `openid` or `User.Read`) return empty string.
+func scopeAudience(scope string) string {
+	scope = strings.TrimSpace(scope)
+	if u, err := url.Parse(scope); err == nil && u.Scheme != "" && u.Host != "" {
+		return strings.ToLower(u.Scheme + "://" + u.Host)
+	}
+	if i := strings.LastIndex(scope, "/"); i > 0 {
+		return strings.ToLower(scope[:i])
+	}
+	return ""
+}
+
+// List distinct audiences of the scopes in sorted order.
+func scopeAudiences(scopes []string) []string {
+	seen := map[string]bool{}
+	audiences := []string{}
+	for _, scope := range scopes {
+		if audience := scopeAudience(scope); audience != "" && !seen[audience] {
+			seen[audience] = true
+			audiences = append(audiences, audience)
+		}
+	}
+	sort.Strings(audiences)
+	return audiences
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+func TestDisallowedScope(t *testing.T) {
+	tests := map[string]struct {
+		scope   string
+		allowed []string
+		want    bool
+	}{
+		"empty allowlist":         {scope: "https://vault.azure.net/.default", want: false},
+		"exact":                   {scope: "https://vault.azure.net/.default", allowed: []string{"https://vault.azure.net/.default"}, want: false},
+		"host":                    {scope: "https://vault.azure.net/.default", allowed: []string{"https://vault.azure.net"}, want: false},
+		"host with slash":         {scope: "https://vault.azure.net/.default", allowed: []string{"https://vault.azure.net/"}, want: false},
+		"look-alike host":         {scope: "https://vault.azure.net.evil.com/.default", allowed: []string{"https://vault.azure.net"}, want: true},
+		"look-alike path":         {scope: "https://example.com/api2/.default", allowed: []string{"https://example.com/api"}, want: true},
+		"other resource":          {scope: "https://management.azure.com/.default", allowed: []string{"https://vault.azure.net"}, want: true},
+		"any of the entries":      {scope: "https://management.azure.com/.default", allowed: []string{"https://vault.azure.net", "https://management.azure.com"}, want: false},
+		"entry longer than scope": {scope: "https://vault.azure.net", allowed: []string{"https://vault.azure.net/.default"}, want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			scope, got := disallowedScope([]string{test.scope}, test.allowed)
+			if got != test.want {
+				t.Errorf("disallowed = %t (%q), want %t", got, scope, test.want)
+			}
+		})
+	}
+}
+
+func TestExpandScopeAliases(t *testing.T) {
+	custom := cloud.Configuration{
+		ActiveDirectoryAuthorityHost: "https://login.example.com/",
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {Audience: "https://management.example.com"},
+			"postgres":            {Audience: "https://ossrdbms-aad.database.example.com/"},
+		},
+	}
+	tests := map[string]struct {
+		scopes    []string
+		cloudName string
+		config    cloud.Configuration
+		want      []string
+		wantErr   bool
+	}{
+		"not an alias":          {scopes: []string{"https://vault.azure.net/.default"}, cloudName: "AzurePublic", config: cloud.AzurePublic, want: []string{"https://vault.azure.net/.default"}},
+		"named cloud":           {scopes: []string{"sql"}, cloudName: "AzureGovernment", config: cloud.AzureGovernment, want: []string{"https://database.usgovcloudapi.net/.default"}},
+		"custom cloud service":  {scopes: []string{"postgres"}, cloudName: customCloudName, config: custom, want: []string{"https://ossrdbms-aad.database.example.com/.default"}},
+		"custom cloud no alias": {scopes: []string{"https://management.example.com/.default"}, cloudName: customCloudName, config: custom, want: []string{"https://management.example.com/.default"}},
+		"custom cloud missing":  {scopes: []string{"sql"}, cloudName: customCloudName, config: custom, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := expandScopeAliases(test.scopes, test.cloudName, test.config)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, want error %t", err, test.wantErr)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("scopes = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
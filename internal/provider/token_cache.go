@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// Cached tokens expiring within the margin are requested again, so consumers get some lifetime out of them.
+const tokenCacheMargin = 5 * time.Minute
+
+// Token issued by the chain, with the credential which issued it.
+type cachedToken struct {
+	token  azcore.AccessToken
+	source string
+}
+
+// tokenCache reuses tokens of identical requests over the lifetime of the configured provider, so tokens with the
+// same scopes requested by multiple blocks (ex. in modules) don't each call Entra ID. It's in memory only, nothing
+// is kept between runs. Ephemeral resources are opened in parallel, so access is synchronized.
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: map[string]cachedToken{}}
+}
+
+// Key of the request, scopes are sorted as their order doesn't change the token.
+func tokenCacheKey(options policy.TokenRequestOptions) string {
+	scopes := slices.Clone(options.Scopes)
+	slices.Sort(scopes)
+	return strings.Join([]string{strings.Join(scopes, " "), options.Claims, strconv.FormatBool(options.EnableCAE), options.TenantID}, "\x00")
+}
+
+// Get a token of an identical request, valid for longer than the margin. Safe to call on nil.
+func (c *tokenCache) get(options policy.TokenRequestOptions) (cachedToken, bool) {
+	if c == nil {
+		return cachedToken{}, false
+	}
+	key := tokenCacheKey(options)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.tokens[key]
+	if !ok {
+		return cachedToken{}, false
+	}
+	if time.Until(cached.token.ExpiresOn) <= tokenCacheMargin {
+		delete(c.tokens, key)
+		return cachedToken{}, false
+	}
+	return cached, true
+}
+
+// Store the token issued for the request. Safe to call on nil.
+func (c *tokenCache) add(options policy.TokenRequestOptions, token azcore.AccessToken, source string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[tokenCacheKey(options)] = cachedToken{token: token, source: source}
+}
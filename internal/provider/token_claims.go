@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Decode the JSON payload of a JWT access token, as it's in the token.
+func decodeTokenPayload(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding token payload: %w", err)
+	}
+	return payload, nil
+}
+
+// Decode claims of a JWT access token. The signature isn't verified, the token comes straight from Entra ID.
+func decodeTokenClaims(token string) (map[string]any, error) {
+	payload, err := decodeTokenPayload(token)
+	if err != nil {
+		return nil, err
+	}
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed parsing token claims: %w", err)
+	}
+	return claims, nil
+}
+
+// Get a claim holding a list of strings, empty if the claim isn't present.
+func stringListClaim(claims map[string]any, name string) []string {
+	values, _ := claims[name].([]any)
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Convert a decoded JSON value to a Terraform value. Objects and lists keep types of their elements, so claims
+// can be used in HCL as they appear in the token.
+func claimValue(value any) attr.Value {
+	switch value := value.(type) {
+	case string:
+		return types.StringValue(value)
+	case bool:
+		return types.BoolValue(value)
+	case float64:
+		return types.NumberValue(big.NewFloat(value))
+	case []any:
+		elementTypes := make([]attr.Type, 0, len(value))
+		elements := make([]attr.Value, 0, len(value))
+		for _, element := range value {
+			converted := claimValue(element)
+			elementTypes = append(elementTypes, converted.Type(context.Background()))
+			elements = append(elements, converted)
+		}
+		return types.TupleValueMust(elementTypes, elements)
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(value))
+		attributes := make(map[string]attr.Value, len(value))
+		for name, element := range value {
+			converted := claimValue(element)
+			attributeTypes[name] = converted.Type(context.Background())
+			attributes[name] = converted
+		}
+		return types.ObjectValueMust(attributeTypes, attributes)
+	}
+	// JSON null, a concrete type is needed inside objects and lists
+	return types.StringNull()
+}
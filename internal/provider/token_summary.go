@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// Fields of the token summary file. The raw token is never written.
+var tokenSummaryFields = []string{"credential", "scopes", "expires_on", "fingerprint"}
+
+// Key of the summary file path in ephemeral resource private data, so it can be removed on close.
+const tokenSummaryPrivateKey = "summary_file"
+
+type tokenSummary struct {
+	Credential  string   `json:"credential,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	ExpiresOn   string   `json:"expires_on,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+}
+
+// Write a JSON summary of the token with the selected fields, readable only by the current user.
+func writeTokenSummary(file string, fields []string, credential string, scopes []string, token azcore.AccessToken) error {
+	summary := tokenSummary{}
+	if slices.Contains(fields, "credential") {
+		summary.Credential = credential
+	}
+	if slices.Contains(fields, "scopes") {
+		summary.Scopes = scopes
+	}
+	if slices.Contains(fields, "expires_on") {
+		summary.ExpiresOn = token.ExpiresOn.UTC().Format(time.RFC3339)
+	}
+	if slices.Contains(fields, "fingerprint") {
+		hash := sha256.Sum256([]byte(token.Token))
+		summary.Fingerprint = "sha256:" + hex.EncodeToString(hash[:])
+	}
+	content, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, content, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps permissions of existing files
+	return os.Chmod(file, 0600)
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Create an HTTP client for token requests. When proxyURL is set, requests are sent through the proxy, except for
+// hosts matching NO_PROXY (or no_proxy), otherwise proxy env variables are used. When rootCAs is set, it replaces
+// the system certificates for TLS verification. The proxy isn't contacted here, so connection problems surface in
+// token requests.
+func newHTTPClient(proxyURL *url.URL, rootCAs *x509.CertPool) *http.Client {
+	transport := &http.Transport{}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	if proxyURL != nil {
+		noProxy := os.Getenv("NO_PROXY")
+		if noProxy == "" {
+			noProxy = os.Getenv("no_proxy")
+		}
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  proxyURL.String(),
+			HTTPSProxy: proxyURL.String(),
+			NoProxy:    noProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+	if rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// Create a pool of the system certificates and the PEM encoded CA certificates, so both public endpoints and
+// endpoints behind an inspecting proxy are trusted.
+func newCACertPool(pem []byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM encoded certificates found")
+	}
+	return pool, nil
+}
+
+// Create the HTTP client for proxy and CA settings of the provider, or nil when none are set.
+func transportClient(data *AzIdentityProviderModel) (*http.Client, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	var proxyURL *url.URL
+	if !data.ProxyURL.IsNull() {
+		var err error
+		if proxyURL, err = url.Parse(data.ProxyURL.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("proxy_url"), "Invalid proxy URL", err.Error())
+		}
+	}
+
+	var rootCAs *x509.CertPool
+	caPath, caPEM := path.Root("ca_cert_pem"), []byte(data.CACertPEM.ValueString())
+	if !data.CACertPath.IsNull() {
+		caPath = path.Root("ca_cert_path")
+		var err error
+		if caPEM, err = os.ReadFile(data.CACertPath.ValueString()); err != nil {
+			diags.AddAttributeError(caPath, "Failed reading CA certificates", err.Error())
+		}
+	}
+	if len(caPEM) > 0 {
+		var err error
+		if rootCAs, err = newCACertPool(caPEM); err != nil {
+			diags.AddAttributeError(caPath, "Invalid CA certificates", err.Error())
+		}
+	}
+
+	if diags.HasError() || (proxyURL == nil && rootCAs == nil) {
+		return nil, diags
+	}
+	return newHTTPClient(proxyURL, rootCAs), diags
+}
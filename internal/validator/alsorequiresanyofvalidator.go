@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = AlsoRequiresAnyOfValidator{}
+)
+
+// AlsoRequiresAnyOfValidator checks that at least one of the attributes matching the expressions is set, when the validated attribute is set.
+// Unlike stringvalidator.AtLeastOneOf, the validated attribute itself doesn't count.
+type AlsoRequiresAnyOfValidator struct {
+	PathExpressions path.Expressions
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v AlsoRequiresAnyOfValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v AlsoRequiresAnyOfValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Also requires at least one of these attributes to be set: %s", v.PathExpressions)
+}
+
+// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v AlsoRequiresAnyOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	names := make([]string, 0, len(v.PathExpressions))
+	for _, expression := range v.PathExpressions {
+		names = append(names, expression.String())
+		matchedPaths, diags := req.Config.PathMatches(ctx, req.PathExpression.Merge(expression))
+		if resp.Diagnostics.Append(diags...); diags.HasError() {
+			return
+		}
+		for _, p := range matchedPaths {
+			var value attr.Value
+			if diags := req.Config.GetAttribute(ctx, p, &value); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+			// Unknown values will be validated once they're known
+			if value.IsUnknown() || !value.IsNull() {
+				return
+			}
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Missing required configuration",
+		fmt.Sprintf("Value '%s' requires at least one of these attributes to be set: %s", req.ConfigValue.ValueString(), strings.Join(names, ", ")),
+	)
+}
+
+func AlsoRequiresAnyOf(expressions ...path.Expression) AlsoRequiresAnyOfValidator {
+	return AlsoRequiresAnyOfValidator{PathExpressions: expressions}
+}
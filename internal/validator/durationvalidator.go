@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = DurationValidator{}
+)
+
+// DurationValidator checks that the value is a Go duration (ex. `5s` or `1m30s`) which isn't negative.
+type DurationValidator struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v DurationValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v DurationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value must be a non-negative duration, ex. `5s` or `1m30s`"
+}
+
+// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v DurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if d, err := time.ParseDuration(req.ConfigValue.ValueString()); err != nil || d < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf("Value '%s' must be a non-negative duration, ex. `5s` or `1m30s`.", req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+func Duration() DurationValidator {
+	return DurationValidator{}
+}
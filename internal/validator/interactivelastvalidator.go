@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ validator.List = InteractiveLastValidator{}
+)
+
+// InteractiveLastValidator warns when an interactive value of the list is followed by non-interactive ones. Credentials
+// are tried in order, so an interactive credential would prompt the user before the non-interactive fallback is reached.
+type InteractiveLastValidator struct {
+	Interactive []string
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v InteractiveLastValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v InteractiveLastValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Interactive values (%s) should be at the end of the list", strings.Join(v.Interactive, ", "))
+}
+
+// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v InteractiveLastValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	firstInteractive := -1
+	for i, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsUnknown() || value.IsNull() {
+			continue
+		}
+		interactive := slices.Contains(v.Interactive, value.ValueString())
+		if interactive && firstInteractive < 0 {
+			firstInteractive = i
+		} else if !interactive && firstInteractive >= 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				req.Path.AtListIndex(firstInteractive),
+				"Interactive credential before non-interactive",
+				fmt.Sprintf("Interactive credentials prompt the user before '%s' is tried. Move interactive credentials to the end of the list, so they're only used when the other credentials fail.", value.ValueString()),
+			)
+			return
+		}
+	}
+}
+
+func InteractiveLast(interactive ...string) InteractiveLastValidator {
+	return InteractiveLastValidator{Interactive: interactive}
+}
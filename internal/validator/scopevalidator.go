@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = ScopeValidator{}
+)
+
+// ScopeValidator warns about scopes which are a bare resource URL (ex. `https://graph.microsoft.com`), without
+// `/.default` or a permission. Entra ID rejects them with a 400 response, which doesn't point at the scope.
+// It's only a warning, so unusual but valid scopes aren't blocked.
+type ScopeValidator struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v ScopeValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v ScopeValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value should be a scope ending with `/.default` or a permission, not a bare resource URL"
+}
+
+// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v ScopeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	scope := req.ConfigValue.ValueString()
+	// Space delimited scopes contain permissions, ex. `https://graph.microsoft.com/User.Read openid`
+	if strings.HasSuffix(scope, "/.default") || strings.Contains(scope, " ") {
+		return
+	}
+	if u, err := url.Parse(scope); err == nil && u.Scheme != "" && u.Host != "" && strings.Trim(u.Path, "/") == "" {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"Scope is a resource URL",
+			fmt.Sprintf("Scope '%s' has neither `/.default` nor a permission, so token requests will likely fail. Use '%s/.default' for all permissions granted to the resource.", scope, strings.TrimSuffix(scope, "/")),
+		)
+	}
+}
+
+func Scope() ScopeValidator {
+	return ScopeValidator{}
+}
@@ -7,25 +7,38 @@ import (
 )
 
 var (
-	_ validator.String = ValueBasedValidator{}
+	_ validator.String  = StringValueBasedValidator{}
+	_ validator.Bool    = BoolValueBasedValidator{}
+	_ validator.Int64   = Int64ValueBasedValidator{}
+	_ validator.Float64 = Float64ValueBasedValidator{}
+	_ validator.Number  = NumberValueBasedValidator{}
 )
 
-type ValueBasedValidator struct {
-	ElementValidators map[string]validator.String
+// ValueBasedValidator holds the validator registered for each value of an attribute, ex. requiring a block only when
+// a list contains a specific string. K is the Go type of the value and V the framework validator interface of the
+// attribute type. Values without a validator are valid. It's embedded by the validator of each attribute type, so
+// element validators of another attribute type don't compile.
+type ValueBasedValidator[K comparable, V any] struct {
+	ElementValidators map[K]V
 }
 
 // Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
-func (v ValueBasedValidator) Description(ctx context.Context) string {
+func (v ValueBasedValidator[K, V]) Description(ctx context.Context) string {
 	return v.MarkdownDescription(ctx)
 }
 
 // MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
-func (v ValueBasedValidator) MarkdownDescription(ctx context.Context) string {
+func (v ValueBasedValidator[K, V]) MarkdownDescription(ctx context.Context) string {
 	return "Uses validators for specific values"
 }
 
-// Validate runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
-func (v ValueBasedValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+// StringValueBasedValidator runs the validator of the string value.
+type StringValueBasedValidator struct {
+	ValueBasedValidator[string, validator.String]
+}
+
+// ValidateString runs the validator of the string value.
+func (v StringValueBasedValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
 	// If the value is unknown or null, there is nothing to validate.
 	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
 		return
@@ -36,6 +49,91 @@ func (v ValueBasedValidator) ValidateString(ctx context.Context, req validator.S
 	}
 }
 
-func ValueBased(validators map[string]validator.String) ValueBasedValidator {
-	return ValueBasedValidator{ElementValidators: validators}
+// BoolValueBasedValidator runs the validator of the bool value.
+type BoolValueBasedValidator struct {
+	ValueBasedValidator[bool, validator.Bool]
+}
+
+// ValidateBool runs the validator of the bool value.
+func (v BoolValueBasedValidator) ValidateBool(ctx context.Context, req validator.BoolRequest, resp *validator.BoolResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if elementValidator, ok := v.ElementValidators[req.ConfigValue.ValueBool()]; ok {
+		elementValidator.ValidateBool(ctx, req, resp)
+	}
+}
+
+// Int64ValueBasedValidator runs the validator of the int64 value.
+type Int64ValueBasedValidator struct {
+	ValueBasedValidator[int64, validator.Int64]
+}
+
+// ValidateInt64 runs the validator of the int64 value.
+func (v Int64ValueBasedValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if elementValidator, ok := v.ElementValidators[req.ConfigValue.ValueInt64()]; ok {
+		elementValidator.ValidateInt64(ctx, req, resp)
+	}
+}
+
+// Float64ValueBasedValidator runs the validator of the float64 value.
+type Float64ValueBasedValidator struct {
+	ValueBasedValidator[float64, validator.Float64]
+}
+
+// ValidateFloat64 runs the validator of the float64 value.
+func (v Float64ValueBasedValidator) ValidateFloat64(ctx context.Context, req validator.Float64Request, resp *validator.Float64Response) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if elementValidator, ok := v.ElementValidators[req.ConfigValue.ValueFloat64()]; ok {
+		elementValidator.ValidateFloat64(ctx, req, resp)
+	}
+}
+
+// NumberValueBasedValidator runs the validator of the number value, looked up by its float64 approximation.
+type NumberValueBasedValidator struct {
+	ValueBasedValidator[float64, validator.Number]
+}
+
+// ValidateNumber runs the validator of the number value, looked up by its float64 approximation.
+func (v NumberValueBasedValidator) ValidateNumber(ctx context.Context, req validator.NumberRequest, resp *validator.NumberResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	value, _ := req.ConfigValue.ValueBigFloat().Float64()
+	if elementValidator, ok := v.ElementValidators[value]; ok {
+		elementValidator.ValidateNumber(ctx, req, resp)
+	}
+}
+
+func ValueBased(validators map[string]validator.String) StringValueBasedValidator {
+	return StringValueBasedValidator{ValueBasedValidator[string, validator.String]{ElementValidators: validators}}
+}
+
+func BoolValueBased(validators map[bool]validator.Bool) BoolValueBasedValidator {
+	return BoolValueBasedValidator{ValueBasedValidator[bool, validator.Bool]{ElementValidators: validators}}
+}
+
+func Int64ValueBased(validators map[int64]validator.Int64) Int64ValueBasedValidator {
+	return Int64ValueBasedValidator{ValueBasedValidator[int64, validator.Int64]{ElementValidators: validators}}
+}
+
+func Float64ValueBased(validators map[float64]validator.Float64) Float64ValueBasedValidator {
+	return Float64ValueBasedValidator{ValueBasedValidator[float64, validator.Float64]{ElementValidators: validators}}
+}
+
+func NumberValueBased(validators map[float64]validator.Number) NumberValueBasedValidator {
+	return NumberValueBasedValidator{ValueBasedValidator[float64, validator.Number]{ElementValidators: validators}}
 }
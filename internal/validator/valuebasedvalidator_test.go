@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Validator failing every value, to see which element validator ran.
+type failingValidator struct {
+	summary string
+}
+
+func (v failingValidator) Description(ctx context.Context) string {
+	return v.summary
+}
+
+func (v failingValidator) MarkdownDescription(ctx context.Context) string {
+	return v.summary
+}
+
+func (v failingValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	resp.Diagnostics.AddAttributeError(req.Path, v.summary, "")
+}
+
+func (v failingValidator) ValidateBool(ctx context.Context, req validator.BoolRequest, resp *validator.BoolResponse) {
+	resp.Diagnostics.AddAttributeError(req.Path, v.summary, "")
+}
+
+func (v failingValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	resp.Diagnostics.AddAttributeError(req.Path, v.summary, "")
+}
+
+// Summary of the single error, or empty when there are no errors.
+func errorSummary(t *testing.T, diags diag.Diagnostics) string {
+	t.Helper()
+	switch diags.ErrorsCount() {
+	case 0:
+		return ""
+	case 1:
+		return diags.Errors()[0].Summary()
+	}
+	t.Fatalf("errors = %v, want at most one", diags.Errors())
+	return ""
+}
+
+func TestValueBasedValidatorBool(t *testing.T) {
+	v := BoolValueBased(map[bool]validator.Bool{true: failingValidator{summary: "true"}})
+	tests := map[string]struct {
+		value types.Bool
+		want  string
+	}{
+		"validated value": {value: types.BoolValue(true), want: "true"},
+		"value without":   {value: types.BoolValue(false)},
+		"null":            {value: types.BoolNull()},
+		"unknown":         {value: types.BoolUnknown()},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.BoolResponse{}
+			v.ValidateBool(context.Background(), validator.BoolRequest{Path: path.Root("test"), ConfigValue: test.value}, resp)
+			got := errorSummary(t, resp.Diagnostics)
+			if got != test.want {
+				t.Errorf("error = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValueBasedValidatorInt64(t *testing.T) {
+	v := Int64ValueBased(map[int64]validator.Int64{1: failingValidator{summary: "one"}, 2: failingValidator{summary: "two"}})
+	tests := map[string]struct {
+		value types.Int64
+		want  string
+	}{
+		"first value":   {value: types.Int64Value(1), want: "one"},
+		"second value":  {value: types.Int64Value(2), want: "two"},
+		"value without": {value: types.Int64Value(3)},
+		"null":          {value: types.Int64Null()},
+		"unknown":       {value: types.Int64Unknown()},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.Int64Response{}
+			v.ValidateInt64(context.Background(), validator.Int64Request{Path: path.Root("test"), ConfigValue: test.value}, resp)
+			got := errorSummary(t, resp.Diagnostics)
+			if got != test.want {
+				t.Errorf("error = %q, want %q", got, test.want)
+			}
+		})
+	}
+}